@@ -44,30 +44,31 @@ func main() {
 		log.Fatalf("initialise connectivity storage: %v", err)
 	}
 
-	mon := monitor.New(time.Duration(cfg.IntervalMinutes)*time.Minute, cfg.Targets, store)
-	mon.Start()
-	defer mon.Stop()
-
-	connMon := monitor.NewConnectivityMonitor(cfg.MonitorDNS, connectivityStore)
+	connMon := monitor.NewConnectivityMonitor(cfg.ConnectivityProbes, connectivityStore)
 	connMon.Start()
 	defer connMon.Stop()
 
-	connectivityInterval := 0
-	if cfg.MonitorDNS.Enabled {
-		connectivityInterval = cfg.MonitorDNS.IntervalSeconds
+	mon := monitor.New(time.Duration(cfg.IntervalMinutes)*time.Minute, cfg.Targets, store)
+	mon.SetMaxParallel(cfg.MaxParallel)
+	mon.SetConnectivity(connMon)
+	if backend := openUnitBackend(cfg.SystemdBackend); backend != nil {
+		defer backend.Close()
+		mon.SetUnitBackend(backend)
 	}
+	mon.Start()
+	defer mon.Stop()
 
 	node := cluster.Node{
-		ID:                          cfg.NodeID,
-		Name:                        cfg.NodeName,
-		IntervalMinutes:             cfg.IntervalMinutes,
-		ConnectivityIntervalSeconds: connectivityInterval,
+		ID:              cfg.NodeID,
+		Name:            cfg.NodeName,
+		IntervalMinutes: cfg.IntervalMinutes,
 	}
 	clusterSvc := cluster.NewService(node, store, cfg, cfg.Targets, connMon)
 	clusterSvc.Start()
 	defer clusterSvc.Stop()
 
 	srv := server.New(*addr, node, store, clusterSvc, cfg.Targets, connMon)
+	srv.SetPingRecorder(mon)
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
@@ -86,3 +87,19 @@ func main() {
 		log.Fatalf("server error: %v", err)
 	}
 }
+
+func openUnitBackend(mode string) monitor.UnitBackend {
+	if mode == config.SystemdBackendSystemctl {
+		return nil
+	}
+	backend, err := monitor.NewDBusBackend()
+	if err != nil {
+		if mode == config.SystemdBackendDBus {
+			log.Fatalf("systemd d-bus backend: %v", err)
+		}
+		log.Printf("systemd d-bus backend unavailable, using systemctl: %v", err)
+		return nil
+	}
+	log.Printf("Using systemd d-bus backend")
+	return backend
+}
@@ -3,8 +3,8 @@ module jobmonitor
 go 1.21
 
 require (
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/gorilla/websocket v1.5.1
+	golang.org/x/net v0.17.0
 	gopkg.in/yaml.v3 v3.0.1
 )
-
-require golang.org/x/net v0.17.0 // indirect
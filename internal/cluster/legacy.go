@@ -0,0 +1,63 @@
+package cluster
+
+import "jobmonitor/internal/models"
+
+// The helpers below fill the single-probe fields that nodes without named connectivity probes
+// still read and send. They can go once every peer reads connectivity_probes and
+// connectivity_timelines.
+
+// LegacyConnectivity returns the sample of the default probe, or of the first probe when the
+// default one is not configured.
+func LegacyConnectivity(samples []models.ConnectivityStatus) *models.ConnectivityStatus {
+	if len(samples) == 0 {
+		return nil
+	}
+	for _, sample := range samples {
+		if sample.ProbeID() == models.DefaultConnectivityProbeID {
+			sample := sample
+			return &sample
+		}
+	}
+	sample := samples[0]
+	return &sample
+}
+
+// LegacyConnectivityTimeline returns the timeline of the default probe, or of the first probe.
+func LegacyConnectivityTimeline(timelines []models.ConnectivityTimeline) []models.TimelinePoint {
+	if len(timelines) == 0 {
+		return nil
+	}
+	for _, tl := range timelines {
+		if tl.ProbeID == models.DefaultConnectivityProbeID {
+			return tl.Timeline
+		}
+	}
+	return timelines[0].Timeline
+}
+
+// LegacyConnectivityInterval returns the check interval of the default probe, or of the first probe.
+func LegacyConnectivityInterval(probes []models.ConnectivityProbe) int {
+	if len(probes) == 0 {
+		return 0
+	}
+	for _, probe := range probes {
+		if probe.ID == models.DefaultConnectivityProbeID {
+			return probe.IntervalSeconds
+		}
+	}
+	return probes[0].IntervalSeconds
+}
+
+// upgradeLegacyConnectivity fills the per-probe fields from the single-probe ones sent by peers
+// that predate named probes.
+func upgradeLegacyConnectivity(status *NodeStatusResponse, history *NodeHistoryResponse) {
+	if len(status.ConnectivityProbes) == 0 && status.Connectivity != nil {
+		status.ConnectivityProbes = []models.ConnectivityStatus{*status.Connectivity}
+	}
+	if len(history.ConnectivityTimelines) == 0 && len(history.ConnectivityTimeline) > 0 {
+		history.ConnectivityTimelines = []models.ConnectivityTimeline{{
+			ProbeID:  models.DefaultConnectivityProbeID,
+			Timeline: history.ConnectivityTimeline,
+		}}
+	}
+}
@@ -166,33 +166,35 @@ func (s *Service) localSnapshot(start, end time.Time) PeerSnapshot {
 		timeline.DefaultTimelinePoints,
 	)
 	services := metrics.ComputeServiceUptime(history, start, end, s.interval, s.targets)
-	var connectivity *models.ConnectivityStatus
+	var connectivity []models.ConnectivityStatus
+	var probes []models.ConnectivityProbe
 	if s.connectivity != nil {
-		if sample, ok := s.connectivity.Latest(); ok {
-			clone := sample
-			connectivity = &clone
-		}
+		connectivity = s.connectivity.Latest()
+		probes = s.connectivity.Probes()
 	}
 	connectivityHistory := s.connectivityHistory(start, end)
-	connectivityTimeline := timeline.BuildConnectivityTimeline(connectivityHistory, start, end, timeline.DefaultTimelinePoints)
+	connectivityTimelines := timeline.BuildConnectivityTimelines(connectivityHistory, probes, start, end, timeline.DefaultTimelinePoints)
 
 	return PeerSnapshot{
 		Node: Node{
 			ID:                          s.node.ID,
 			Name:                        s.node.Name,
 			IntervalMinutes:             int(s.interval / time.Minute),
-			ConnectivityIntervalSeconds: s.node.ConnectivityIntervalSeconds,
+			ConnectivityIntervalSeconds: LegacyConnectivityInterval(probes),
 		},
-		Status:               status,
-		Connectivity:         connectivity,
-		ConnectivityHistory:  connectivityHistory,
-		ConnectivityTimeline: connectivityTimeline,
-		History:              nil,
-		ServiceTimelines:     timelines,
-		Services:             services,
-		Targets:              s.targets,
-		UpdatedAt:            time.Now().UTC(),
-		Source:               "local",
+		Status:                status,
+		ConnectivityProbes:    connectivity,
+		ConnectivityHistory:   connectivityHistory,
+		ConnectivityTimelines: connectivityTimelines,
+		Probes:                probes,
+		History:               nil,
+		ServiceTimelines:      timelines,
+		Services:              services,
+		Targets:               s.targets,
+		UpdatedAt:             time.Now().UTC(),
+		Source:                "local",
+		Connectivity:          LegacyConnectivity(connectivity),
+		ConnectivityTimeline:  LegacyConnectivityTimeline(connectivityTimelines),
 	}
 }
 
@@ -208,7 +210,7 @@ func (s *Service) materialisePeerSnapshot(snapshot PeerSnapshot, start, end time
 	}
 	services := metrics.ComputeServiceUptime(history, start, endpoint, interval, snapshot.Targets)
 	connectivityHistory := filterConnectivityHistory(snapshot.ConnectivityHistory, start, end)
-	connectivityTimeline := timeline.BuildConnectivityTimeline(connectivityHistory, start, end, timeline.DefaultTimelinePoints)
+	connectivityTimelines := timeline.BuildConnectivityTimelines(connectivityHistory, snapshot.Probes, start, end, timeline.DefaultTimelinePoints)
 
 	timelines := timeline.BuildServiceTimelines(
 		history,
@@ -219,18 +221,21 @@ func (s *Service) materialisePeerSnapshot(snapshot PeerSnapshot, start, end time
 		timeline.DefaultTimelinePoints,
 	)
 	return PeerSnapshot{
-		Node:                 snapshot.Node,
-		Status:               snapshot.Status,
-		Connectivity:         snapshot.Connectivity,
-		ConnectivityHistory:  connectivityHistory,
-		ConnectivityTimeline: connectivityTimeline,
-		History:              nil,
-		ServiceTimelines:     timelines,
-		Services:             services,
-		Targets:              snapshot.Targets,
-		UpdatedAt:            snapshot.UpdatedAt,
-		Error:                snapshot.Error,
-		Source:               snapshot.Source,
+		Node:                  snapshot.Node,
+		Status:                snapshot.Status,
+		ConnectivityProbes:    snapshot.ConnectivityProbes,
+		ConnectivityHistory:   connectivityHistory,
+		ConnectivityTimelines: connectivityTimelines,
+		Probes:                snapshot.Probes,
+		History:               nil,
+		ServiceTimelines:      timelines,
+		Services:              services,
+		Targets:               snapshot.Targets,
+		UpdatedAt:             snapshot.UpdatedAt,
+		Error:                 snapshot.Error,
+		Source:                snapshot.Source,
+		Connectivity:          LegacyConnectivity(snapshot.ConnectivityProbes),
+		ConnectivityTimeline:  LegacyConnectivityTimeline(connectivityTimelines),
 	}
 }
 
@@ -273,6 +278,13 @@ func (s *Service) fetchPeer(peer config.Peer) error {
 		return fmt.Errorf("history fetch failed: %w", err)
 	}
 
+	upgradeLegacyConnectivity(&statusResp, &historyResp)
+
+	probes := statusResp.Probes
+	if len(probes) == 0 {
+		probes = historyResp.Probes
+	}
+
 	targets := statusResp.Targets
 	if len(targets) == 0 {
 		targets = historyResp.Targets
@@ -289,14 +301,15 @@ func (s *Service) fetchPeer(peer config.Peer) error {
 			IntervalMinutes:             statusResp.Node.IntervalMinutes,
 			ConnectivityIntervalSeconds: statusResp.Node.ConnectivityIntervalSeconds,
 		},
-		Status:               statusResp.Status,
-		Connectivity:         statusResp.Connectivity,
-		ConnectivityHistory:  historyResp.Connectivity,
-		ConnectivityTimeline: historyResp.ConnectivityTimeline,
-		History:              capHistory(historyResp.History, s.historyCap),
-		Targets:              targets,
-		UpdatedAt:            time.Now().UTC(),
-		Source:               "peer",
+		Status:                statusResp.Status,
+		ConnectivityProbes:    statusResp.ConnectivityProbes,
+		ConnectivityHistory:   historyResp.Connectivity,
+		ConnectivityTimelines: historyResp.ConnectivityTimelines,
+		Probes:                probes,
+		History:               capHistory(historyResp.History, s.historyCap),
+		Targets:               targets,
+		UpdatedAt:             time.Now().UTC(),
+		Source:                "peer",
 	}
 	s.mu.Unlock()
 	return nil
@@ -10,10 +10,11 @@ import (
 
 // Node describes a JobMonitor instance.
 type Node struct {
-	ID                          string `json:"id"`
-	Name                        string `json:"name"`
-	IntervalMinutes             int    `json:"interval_minutes"`
-	ConnectivityIntervalSeconds int    `json:"connectivity_interval_seconds,omitempty"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	IntervalMinutes int    `json:"interval_minutes"`
+	// Deprecated: use the interval of the entries in Probes.
+	ConnectivityIntervalSeconds int `json:"connectivity_interval_seconds,omitempty"`
 }
 
 // Peer wraps configuration for a remote node.
@@ -23,24 +24,31 @@ type Peer struct {
 
 // NodeStatusResponse describes the payload exposed by /api/node/status.
 type NodeStatusResponse struct {
-	Node         Node                       `json:"node"`
-	Status       *models.StatusEntry        `json:"status,omitempty"`
+	Node               Node                        `json:"node"`
+	Status             *models.StatusEntry         `json:"status,omitempty"`
+	ConnectivityProbes []models.ConnectivityStatus `json:"connectivity_probes,omitempty"`
+	Probes             []models.ConnectivityProbe  `json:"probes,omitempty"`
+	Targets            []models.Target             `json:"targets,omitempty"`
+	GeneratedAt        time.Time                   `json:"generated_at"`
+	// Deprecated: use ConnectivityProbes.
 	Connectivity *models.ConnectivityStatus `json:"connectivity,omitempty"`
-	Targets      []models.Target            `json:"targets,omitempty"`
-	GeneratedAt  time.Time                  `json:"generated_at"`
 }
 
 // NodeHistoryResponse describes history payload from /api/node/history.
 type NodeHistoryResponse struct {
-	Node                 Node                        `json:"node"`
-	History              []models.StatusEntry        `json:"history"`
-	Connectivity         []models.ConnectivityStatus `json:"connectivity,omitempty"`
-	ConnectivityTimeline []models.TimelinePoint      `json:"connectivity_timeline,omitempty"`
-	GeneratedAt          time.Time                   `json:"generated_at"`
-	Range                string                      `json:"range"`
-	RangeStart           time.Time                   `json:"range_start"`
-	RangeEnd             time.Time                   `json:"range_end"`
-	Targets              []models.Target             `json:"targets,omitempty"`
+	Node                  Node                          `json:"node"`
+	History               []models.StatusEntry          `json:"history"`
+	ServiceTimelines      []models.ServiceTimeline      `json:"service_timelines,omitempty"`
+	Connectivity          []models.ConnectivityStatus   `json:"connectivity,omitempty"`
+	ConnectivityTimelines []models.ConnectivityTimeline `json:"connectivity_timelines,omitempty"`
+	Probes                []models.ConnectivityProbe    `json:"probes,omitempty"`
+	GeneratedAt           time.Time                     `json:"generated_at"`
+	Range                 string                        `json:"range"`
+	RangeStart            time.Time                     `json:"range_start"`
+	RangeEnd              time.Time                     `json:"range_end"`
+	Targets               []models.Target               `json:"targets,omitempty"`
+	// Deprecated: use ConnectivityTimelines.
+	ConnectivityTimeline []models.TimelinePoint `json:"connectivity_timeline,omitempty"`
 }
 
 // NodeUptimeResponse describes uptime payload from /api/node/uptime.
@@ -55,18 +63,23 @@ type NodeUptimeResponse struct {
 
 // PeerSnapshot stores last known data for a peer.
 type PeerSnapshot struct {
-	Node                 Node                        `json:"node"`
-	Status               *models.StatusEntry         `json:"status,omitempty"`
-	Connectivity         *models.ConnectivityStatus  `json:"connectivity,omitempty"`
-	ConnectivityHistory  []models.ConnectivityStatus `json:"connectivity_history,omitempty"`
-	ConnectivityTimeline []models.TimelinePoint      `json:"connectivity_timeline,omitempty"`
-	History              []models.StatusEntry        `json:"history,omitempty"`
-	ServiceTimelines     []models.ServiceTimeline    `json:"service_timelines,omitempty"`
-	Services             []metrics.ServiceUptime     `json:"services"`
-	Targets              []models.Target             `json:"targets,omitempty"`
-	UpdatedAt            time.Time                   `json:"updated_at"`
-	Error                string                      `json:"error,omitempty"`
-	Source               string                      `json:"source"`
+	Node                  Node                          `json:"node"`
+	Status                *models.StatusEntry           `json:"status,omitempty"`
+	ConnectivityProbes    []models.ConnectivityStatus   `json:"connectivity_probes,omitempty"`
+	ConnectivityHistory   []models.ConnectivityStatus   `json:"connectivity_history,omitempty"`
+	ConnectivityTimelines []models.ConnectivityTimeline `json:"connectivity_timelines,omitempty"`
+	Probes                []models.ConnectivityProbe    `json:"probes,omitempty"`
+	History               []models.StatusEntry          `json:"history,omitempty"`
+	ServiceTimelines      []models.ServiceTimeline      `json:"service_timelines,omitempty"`
+	Services              []metrics.ServiceUptime       `json:"services"`
+	Targets               []models.Target               `json:"targets,omitempty"`
+	UpdatedAt             time.Time                     `json:"updated_at"`
+	Error                 string                        `json:"error,omitempty"`
+	Source                string                        `json:"source"`
+	// Deprecated: use ConnectivityProbes.
+	Connectivity *models.ConnectivityStatus `json:"connectivity,omitempty"`
+	// Deprecated: use ConnectivityTimelines.
+	ConnectivityTimeline []models.TimelinePoint `json:"connectivity_timeline,omitempty"`
 }
 
 // ClusterSnapshot is returned by /api/cluster.
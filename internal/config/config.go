@@ -3,12 +3,17 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"jobmonitor/internal/models"
+	"jobmonitor/internal/schedule"
 )
 
 // Config represents configuration data for the monitoring service.
@@ -17,20 +22,51 @@ type Config struct {
 	DataDirectory   string          `yaml:"data_directory"`
 	NodeID          string          `yaml:"node_id"`
 	NodeName        string          `yaml:"node_name"`
+	SystemdBackend  string          `yaml:"systemd_backend"`
+	MaxParallel     int             `yaml:"max_parallel_checks"`
 	MonitorDNS      MonitorDNS      `yaml:"monitor_dns"`
 	Peers           []Peer          `yaml:"peers"`
 	PeerRefreshSec  int             `yaml:"peer_refresh_seconds"`
 	Targets         []models.Target `yaml:"targets"`
+
+	// ConnectivityProbes lists named connectivity probes. After Load it also contains the
+	// probe defined by an enabled monitor_dns section, with id "dns".
+	ConnectivityProbes []models.ConnectivityProbe `yaml:"connectivity_probes"`
 }
 
-// MonitorDNS defines optional connectivity probing against a DNS resolver.
+// Systemd backends selectable via systemd_backend.
+const (
+	SystemdBackendAuto      = "auto"
+	SystemdBackendDBus      = "dbus"
+	SystemdBackendSystemctl = "systemctl"
+)
+
+// MonitorDNS defines optional connectivity probing that resolves queries against a DNS resolver.
 type MonitorDNS struct {
-	Enabled         bool   `yaml:"enabled"`
-	Target          string `yaml:"target"`
-	IntervalSeconds int    `yaml:"interval_seconds"`
-	TimeoutSeconds  int    `yaml:"timeout_seconds"`
+	Enabled         bool              `yaml:"enabled"`
+	Target          string            `yaml:"target"`
+	IntervalSeconds int               `yaml:"interval_seconds"`
+	TimeoutSeconds  int               `yaml:"timeout_seconds"`
+	Queries         []models.DNSQuery `yaml:"queries"`
 }
 
+// Connectivity probe defaults applied when a probe leaves them unset.
+const (
+	defaultProbeIntervalSeconds = 60
+	defaultProbeTimeoutSeconds  = 4
+)
+
+// Remediation defaults applied when a target's remediation block leaves them unset.
+const (
+	defaultRemediationAfterFailures = 3
+	defaultRemediationCooldown      = 600
+	defaultRemediationMaxPerHour    = 3
+	defaultRemediationTimeout       = 60
+)
+
+// defaultDNSQuery is resolved by the connectivity probe when no queries are configured.
+var defaultDNSQuery = models.DNSQuery{Name: "example.com", Type: "A"}
+
 // Peer defines a remote JobMonitor instance to aggregate.
 type Peer struct {
 	ID      string `yaml:"id"`
@@ -48,8 +84,9 @@ func DefaultConfig() Config {
 	}
 	defaultDNS := MonitorDNS{
 		Target:          "1.1.1.1",
-		IntervalSeconds: 60,
-		TimeoutSeconds:  4,
+		IntervalSeconds: defaultProbeIntervalSeconds,
+		TimeoutSeconds:  defaultProbeTimeoutSeconds,
+		Queries:         []models.DNSQuery{defaultDNSQuery},
 	}
 
 	return Config{
@@ -57,6 +94,8 @@ func DefaultConfig() Config {
 		DataDirectory:   filepath.Join(".dist", "data"),
 		NodeID:          hostname,
 		NodeName:        hostname,
+		SystemdBackend:  SystemdBackendAuto,
+		MaxParallel:     8,
 		MonitorDNS:      defaultDNS,
 		PeerRefreshSec:  60,
 		Targets: []models.Target{
@@ -100,6 +139,16 @@ func Load(path string) (Config, error) {
 	if cfg.NodeName == "" {
 		cfg.NodeName = cfg.NodeID
 	}
+	switch cfg.SystemdBackend {
+	case "":
+		cfg.SystemdBackend = SystemdBackendAuto
+	case SystemdBackendAuto, SystemdBackendDBus, SystemdBackendSystemctl:
+	default:
+		return Config{}, fmt.Errorf("unknown systemd_backend %q", cfg.SystemdBackend)
+	}
+	if cfg.MaxParallel <= 0 {
+		cfg.MaxParallel = DefaultConfig().MaxParallel
+	}
 	if cfg.PeerRefreshSec <= 0 {
 		cfg.PeerRefreshSec = 60
 	}
@@ -112,14 +161,48 @@ func Load(path string) (Config, error) {
 	if cfg.MonitorDNS.TimeoutSeconds <= 0 {
 		cfg.MonitorDNS.TimeoutSeconds = DefaultConfig().MonitorDNS.TimeoutSeconds
 	}
+	if len(cfg.MonitorDNS.Queries) == 0 {
+		cfg.MonitorDNS.Queries = []models.DNSQuery{defaultDNSQuery}
+	}
+	for _, query := range cfg.MonitorDNS.Queries {
+		if err := validateDNSQuery(query); err != nil {
+			return Config{}, fmt.Errorf("monitor_dns: %w", err)
+		}
+	}
+	if cfg.MonitorDNS.Enabled {
+		probe := models.ConnectivityProbe{
+			ID:              models.DefaultConnectivityProbeID,
+			Name:            "DNS",
+			Type:            models.TargetTypeDNS,
+			Target:          cfg.MonitorDNS.Target,
+			IntervalSeconds: cfg.MonitorDNS.IntervalSeconds,
+			TimeoutSeconds:  cfg.MonitorDNS.TimeoutSeconds,
+			Queries:         cfg.MonitorDNS.Queries,
+		}
+		cfg.ConnectivityProbes = append([]models.ConnectivityProbe{probe}, cfg.ConnectivityProbes...)
+	}
+	probeIDs := make(map[string]bool, len(cfg.ConnectivityProbes))
+	for i := range cfg.ConnectivityProbes {
+		probe := &cfg.ConnectivityProbes[i]
+		if err := normalizeProbe(probe); err != nil {
+			return Config{}, err
+		}
+		if probeIDs[probe.ID] {
+			return Config{}, fmt.Errorf("connectivity probe %s is defined twice", probe.ID)
+		}
+		probeIDs[probe.ID] = true
+	}
 	if len(cfg.Targets) == 0 {
 		return Config{}, errors.New("configuration must define at least one target")
 	}
-	for _, t := range cfg.Targets {
-		if t.Service == "" {
-			return Config{}, errors.New("each target must define a service name")
+	for i := range cfg.Targets {
+		if err := normalizeTarget(&cfg.Targets[i]); err != nil {
+			return Config{}, err
 		}
 	}
+	if err := validateDependencies(cfg.Targets, probeIDs); err != nil {
+		return Config{}, err
+	}
 	for i, peer := range cfg.Peers {
 		if !peer.Enabled {
 			continue
@@ -133,3 +216,370 @@ func Load(path string) (Config, error) {
 	}
 	return cfg, nil
 }
+
+func normalizeTarget(t *models.Target) error {
+	t.Type = strings.ToLower(strings.TrimSpace(t.Type))
+	if t.Type == "" {
+		t.Type = models.TargetTypeSystemd
+	}
+	if t.WatchSeconds < 0 {
+		return fmt.Errorf("target %s: watch_seconds must not be negative", t.ID)
+	}
+	if t.IntervalSeconds < 0 {
+		return fmt.Errorf("target %s: interval_seconds must not be negative", t.ID)
+	}
+	if t.Retries < 0 || t.RetryDelaySeconds < 0 || t.FailAfter < 0 || t.RecoverAfter < 0 {
+		return fmt.Errorf("target %s: retries, retry_delay_seconds, fail_after and recover_after must not be negative", t.ID)
+	}
+	if t.Schedule != "" {
+		if t.IntervalSeconds > 0 {
+			return fmt.Errorf("target %s: set either interval_seconds or schedule, not both", t.ID)
+		}
+		if _, err := schedule.Parse(t.Schedule); err != nil {
+			return fmt.Errorf("target %s: %w", t.ID, err)
+		}
+	}
+	if t.Journal != nil && t.Type != models.TargetTypeSystemd {
+		return fmt.Errorf("target %s: journal is only valid for systemd targets", t.ID)
+	}
+	switch t.Type {
+	case models.TargetTypeSystemd:
+		if t.Service == "" {
+			return errors.New("each target must define a service name")
+		}
+		t.Kind = strings.ToLower(strings.TrimSpace(t.Kind))
+		switch t.Kind {
+		case "":
+			t.Kind = models.UnitKindService
+		case models.UnitKindService, models.UnitKindOneshot, models.UnitKindTimer:
+		default:
+			return fmt.Errorf("target %s: unknown kind %q", t.ID, t.Kind)
+		}
+		if t.Timer != "" && t.Kind != models.UnitKindTimer {
+			return fmt.Errorf("target %s: timer is only valid with kind timer", t.ID)
+		}
+		if t.Host == "" && (t.SSH != nil || t.Port != 0) {
+			return fmt.Errorf("target %s: ssh and port require a host", t.ID)
+		}
+		if t.Port < 0 || t.Port > 65535 {
+			return fmt.Errorf("target %s: invalid ssh port %d", t.ID, t.Port)
+		}
+		if opts := t.Journal; opts != nil {
+			if opts.Priority == "" {
+				opts.Priority = "err"
+			}
+			if _, ok := models.ParseJournalPriority(opts.Priority); !ok {
+				return fmt.Errorf("target %s: unknown journal priority %q", t.ID, opts.Priority)
+			}
+			if _, err := regexp.Compile(opts.Pattern); err != nil {
+				return fmt.Errorf("target %s: invalid journal pattern: %w", t.ID, err)
+			}
+			if opts.WindowSeconds < 0 || (opts.WarnAbove != nil && *opts.WarnAbove < 0) || (opts.ErrorAbove != nil && *opts.ErrorAbove < 0) {
+				return fmt.Errorf("target %s: journal window and thresholds must not be negative", t.ID)
+			}
+			if opts.WarnAbove == nil && opts.ErrorAbove == nil {
+				// Without thresholds any counted line is an error.
+				opts.ErrorAbove = new(int)
+			}
+		}
+	case models.TargetTypeHTTP:
+		if t.URL == "" {
+			return fmt.Errorf("target %s: http targets require a url", t.ID)
+		}
+		if t.HTTP != nil && t.HTTP.BodyRegex != "" {
+			if _, err := regexp.Compile(t.HTTP.BodyRegex); err != nil {
+				return fmt.Errorf("target %s: invalid body_regex: %w", t.ID, err)
+			}
+		}
+	case models.TargetTypeTCP, models.TargetTypeUDP:
+		if t.Host == "" || t.Port <= 0 || t.Port > 65535 {
+			return fmt.Errorf("target %s: %s targets require host and a valid port", t.ID, t.Type)
+		}
+		if t.UDP != nil && t.UDP.ExpectContains != "" {
+			// Matching a reply requires waiting for one.
+			t.UDP.ExpectReply = true
+		}
+	case models.TargetTypeTLS:
+		if t.Host == "" || t.Port < 0 || t.Port > 65535 {
+			return fmt.Errorf("target %s: tls targets require host and a valid port", t.ID)
+		}
+	case models.TargetTypeDNS:
+		if t.Host == "" || t.DNS == nil {
+			return fmt.Errorf("target %s: dns targets require host and a dns query", t.ID)
+		}
+		if err := validateDNSQuery(*t.DNS); err != nil {
+			return fmt.Errorf("target %s: %w", t.ID, err)
+		}
+	case models.TargetTypeCommand:
+		if t.Command == "" {
+			return fmt.Errorf("target %s: command targets require a command", t.ID)
+		}
+	case models.TargetTypeFile:
+		if t.Path == "" {
+			return fmt.Errorf("target %s: file targets require a path", t.ID)
+		}
+		if opts := t.File; opts != nil {
+			if opts.MaxAgeSeconds < 0 || opts.MinBytes < 0 || opts.MaxBytes < 0 || opts.TailLines < 0 {
+				return fmt.Errorf("target %s: file limits must not be negative", t.ID)
+			}
+			for _, pattern := range []string{opts.Contains, opts.NotContains} {
+				if _, err := regexp.Compile(pattern); err != nil {
+					return fmt.Errorf("target %s: invalid file pattern: %w", t.ID, err)
+				}
+			}
+		}
+	case models.TargetTypeContainer:
+		if t.Container == nil || t.Container.Name == "" {
+			return fmt.Errorf("target %s: container targets require container.name", t.ID)
+		}
+		if t.Container.Socket == "" {
+			t.Container.Socket = models.DefaultContainerSocket
+		}
+	case models.TargetTypeResource:
+		if err := normalizeResource(t); err != nil {
+			return fmt.Errorf("target %s: %w", t.ID, err)
+		}
+	case models.TargetTypeProcess:
+		opts := t.Process
+		if opts == nil || (opts.Name == "" && opts.Cmdline == "" && opts.Pidfile == "") {
+			return fmt.Errorf("target %s: process targets require process.name, cmdline or pidfile", t.ID)
+		}
+		if _, err := regexp.Compile(opts.Cmdline); err != nil {
+			return fmt.Errorf("target %s: invalid process.cmdline: %w", t.ID, err)
+		}
+		if opts.MinInstances < 0 || opts.MaxInstances < 0 || opts.MaxRSSMB < 0 || opts.MaxCPUPercent < 0 {
+			return fmt.Errorf("target %s: process limits must not be negative", t.ID)
+		}
+		if opts.MinInstances == 0 {
+			opts.MinInstances = 1
+		}
+		if opts.MaxInstances > 0 && opts.MaxInstances < opts.MinInstances {
+			return fmt.Errorf("target %s: process.max_instances must not be below min_instances", t.ID)
+		}
+	case models.TargetTypeHeartbeat:
+		if t.Heartbeat == nil || t.Heartbeat.PeriodSeconds <= 0 {
+			return fmt.Errorf("target %s: heartbeat targets require heartbeat.period_seconds", t.ID)
+		}
+		if t.Heartbeat.GraceSeconds < 0 || t.Heartbeat.MaxRuntimeSeconds < 0 {
+			return fmt.Errorf("target %s: grace_seconds and max_runtime_seconds must not be negative", t.ID)
+		}
+		if t.Heartbeat.Token == "" {
+			return fmt.Errorf("target %s: heartbeat targets require heartbeat.token", t.ID)
+		}
+	default:
+		validate, ok := models.LookupTargetType(t.Type)
+		if !ok {
+			return fmt.Errorf("target %s: unknown type %q", t.ID, t.Type)
+		}
+		if validate != nil {
+			if err := validate(*t); err != nil {
+				return fmt.Errorf("target %s: %w", t.ID, err)
+			}
+		}
+	}
+	if t.Remediation != nil {
+		if err := normalizeRemediation(t); err != nil {
+			return fmt.Errorf("target %s: %w", t.ID, err)
+		}
+	}
+	return nil
+}
+
+func normalizeRemediation(t *models.Target) error {
+	opts := t.Remediation
+	opts.Action = strings.ToLower(strings.TrimSpace(opts.Action))
+	switch opts.Action {
+	case models.RemediationRestart:
+		if t.Type != models.TargetTypeSystemd {
+			return errors.New("remediation action restart is only valid for systemd targets")
+		}
+		if opts.Command != "" {
+			return errors.New("remediation.command is only valid with action command")
+		}
+	case models.RemediationCommand:
+		if opts.Command == "" {
+			return errors.New("remediation action command requires a command")
+		}
+	default:
+		return fmt.Errorf("unknown remediation.action %q", opts.Action)
+	}
+	if opts.AfterFailures < 0 || opts.CooldownSeconds < 0 || opts.MaxPerHour < 0 || opts.TimeoutSeconds < 0 {
+		return errors.New("remediation limits must not be negative")
+	}
+	if opts.AfterFailures == 0 {
+		opts.AfterFailures = defaultRemediationAfterFailures
+	}
+	if opts.CooldownSeconds == 0 {
+		opts.CooldownSeconds = defaultRemediationCooldown
+	}
+	if opts.MaxPerHour == 0 {
+		opts.MaxPerHour = defaultRemediationMaxPerHour
+	}
+	if opts.TimeoutSeconds == 0 {
+		opts.TimeoutSeconds = defaultRemediationTimeout
+	}
+	return nil
+}
+
+func normalizeResource(t *models.Target) error {
+	opts := t.Resource
+	if opts == nil {
+		return errors.New("resource targets require a resource block")
+	}
+	opts.Kind = strings.ToLower(strings.TrimSpace(opts.Kind))
+	windows := map[int]bool{0: true}
+	switch opts.Kind {
+	case models.ResourceDisk, models.ResourceInodes:
+		if t.Path == "" {
+			t.Path = "/"
+		}
+	case models.ResourceMemory, models.ResourceSwap:
+	case models.ResourceLoad:
+		if opts.Window == 0 {
+			opts.Window = 5
+		}
+		windows = map[int]bool{1: true, 5: true, 15: true}
+	case models.ResourcePSI:
+		opts.Pressure = strings.ToLower(opts.Pressure)
+		switch opts.Pressure {
+		case "":
+			opts.Pressure = "cpu"
+		case "cpu", "memory", "io":
+		default:
+			return fmt.Errorf("unknown resource.pressure %q", opts.Pressure)
+		}
+		if opts.Window == 0 {
+			opts.Window = 60
+		}
+		windows = map[int]bool{10: true, 60: true, 300: true}
+	default:
+		return fmt.Errorf("unknown resource.kind %q", opts.Kind)
+	}
+	if !windows[opts.Window] {
+		return fmt.Errorf("resource.window %d is not supported for %s", opts.Window, opts.Kind)
+	}
+	if opts.Warning < 0 || opts.Critical < 0 || (opts.Warning == 0 && opts.Critical == 0) {
+		return errors.New("resource targets require a positive warning or critical threshold")
+	}
+	if opts.Warning > 0 && opts.Critical > 0 && opts.Warning > opts.Critical {
+		return errors.New("resource.warning must not exceed resource.critical")
+	}
+	return nil
+}
+
+func normalizeProbe(p *models.ConnectivityProbe) error {
+	p.ID = strings.TrimSpace(p.ID)
+	if p.ID == "" {
+		return errors.New("each connectivity probe must define an id")
+	}
+	if p.Name == "" {
+		p.Name = p.ID
+	}
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	if p.Type == "" {
+		p.Type = models.TargetTypeDNS
+	}
+	p.Target = strings.TrimSpace(p.Target)
+	if p.Target == "" {
+		return fmt.Errorf("connectivity probe %s: target is required", p.ID)
+	}
+	if p.IntervalSeconds <= 0 {
+		p.IntervalSeconds = defaultProbeIntervalSeconds
+	}
+	if p.TimeoutSeconds <= 0 {
+		p.TimeoutSeconds = defaultProbeTimeoutSeconds
+	}
+	switch p.Type {
+	case models.TargetTypeDNS:
+		if len(p.Queries) == 0 {
+			p.Queries = []models.DNSQuery{defaultDNSQuery}
+		}
+		for _, query := range p.Queries {
+			if err := validateDNSQuery(query); err != nil {
+				return fmt.Errorf("connectivity probe %s: %w", p.ID, err)
+			}
+		}
+	case models.TargetTypeTCP:
+		if _, _, err := net.SplitHostPort(p.Target); err != nil {
+			return fmt.Errorf("connectivity probe %s: tcp target must be host:port: %w", p.ID, err)
+		}
+	case models.TargetTypeHTTP:
+		u, err := url.Parse(p.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("connectivity probe %s: http target must be an http(s) url", p.ID)
+		}
+	default:
+		return fmt.Errorf("connectivity probe %s: unknown type %q", p.ID, p.Type)
+	}
+	return nil
+}
+
+func validateDNSQuery(query models.DNSQuery) error {
+	if strings.TrimSpace(query.Name) == "" {
+		return errors.New("dns query name is required")
+	}
+	switch strings.ToUpper(strings.TrimSpace(query.Type)) {
+	case "", "A", "AAAA", "MX", "TXT", "CNAME", "NS":
+		return nil
+	default:
+		return fmt.Errorf("unsupported dns record type %q", query.Type)
+	}
+}
+
+// validateDependencies checks that depends_on names existing targets or connectivity probes
+// and that dependencies between targets do not form a cycle.
+func validateDependencies(targets []models.Target, probeIDs map[string]bool) error {
+	byID := make(map[string]models.Target, len(targets))
+	for _, t := range targets {
+		if _, ok := byID[t.ID]; ok {
+			return fmt.Errorf("target %s is defined twice", t.ID)
+		}
+		byID[t.ID] = t
+	}
+	for _, t := range targets {
+		for _, dep := range t.DependsOn {
+			_, isTarget := byID[dep]
+			switch {
+			case dep == t.ID:
+				return fmt.Errorf("target %s: depends_on must not name the target itself", t.ID)
+			case isTarget && probeIDs[dep]:
+				return fmt.Errorf("target %s: depends_on %q names both a target and a connectivity probe", t.ID, dep)
+			case !isTarget && !probeIDs[dep]:
+				return fmt.Errorf("target %s: depends_on %q is neither a target nor a connectivity probe", t.ID, dep)
+			}
+		}
+	}
+
+	// Depth-first search; a target reached again while still on the path closes a cycle.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int, len(targets))
+	var visit func(id string, path []string) error
+	visit = func(id string, path []string) error {
+		switch marks[id] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, id), " -> "))
+		case done:
+			return nil
+		}
+		marks[id] = visiting
+		for _, dep := range byID[id].DependsOn {
+			if _, ok := byID[dep]; !ok {
+				continue
+			}
+			if err := visit(dep, append(path, id)); err != nil {
+				return err
+			}
+		}
+		marks[id] = done
+		return nil
+	}
+	for _, t := range targets {
+		if err := visit(t.ID, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
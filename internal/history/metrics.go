@@ -0,0 +1,134 @@
+package history
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// maxMetricSeries bounds how many metrics are aggregated per service.
+const maxMetricSeries = 6
+
+// latencyMetric is the metric added for checks that measure latency. It is always kept.
+const latencyMetric = "latency"
+
+type metricSample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// metricHistory collects the samples of every metric reported by one service.
+type metricHistory struct {
+	units   map[string]string
+	samples map[string][]metricSample
+}
+
+func (h *metricHistory) add(ts time.Time, check models.CheckResult) {
+	for name, metric := range checkMetrics(check) {
+		if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) {
+			continue
+		}
+		if h.samples == nil {
+			h.units = make(map[string]string)
+			h.samples = make(map[string][]metricSample)
+		}
+		h.units[name] = metric.Unit
+		h.samples[name] = append(h.samples[name], metricSample{Timestamp: ts, Value: metric.Value})
+	}
+}
+
+// checkMetrics returns the metrics of a result, adding its latency when the check measured one.
+func checkMetrics(check models.CheckResult) map[string]models.Metric {
+	if check.LatencyMs <= 0 {
+		return check.Metrics
+	}
+	if _, ok := check.Metrics[latencyMetric]; ok {
+		return check.Metrics
+	}
+	merged := make(map[string]models.Metric, len(check.Metrics)+1)
+	for name, metric := range check.Metrics {
+		merged[name] = metric
+	}
+	merged[latencyMetric] = models.Metric{Value: float64(check.LatencyMs), Unit: "ms"}
+	return merged
+}
+
+// buildMetricSeries aggregates each metric over the same buckets as buildTimeline. Latency comes
+// first, the other metrics follow by name up to maxMetricSeries; it also returns how many
+// metrics were left out.
+func buildMetricSeries(history metricHistory, start, end time.Time, points int) ([]models.MetricSeries, int) {
+	if len(history.samples) == 0 || points <= 0 {
+		return nil, 0
+	}
+	names := make([]string, 0, len(history.samples))
+	for name := range history.samples {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == latencyMetric) != (names[j] == latencyMetric) {
+			return names[i] == latencyMetric
+		}
+		return names[i] < names[j]
+	})
+	omitted := 0
+	if len(names) > maxMetricSeries {
+		omitted = len(names) - maxMetricSeries
+		names = names[:maxMetricSeries]
+	}
+
+	bucketDuration := end.Sub(start) / time.Duration(points)
+	if bucketDuration <= 0 {
+		bucketDuration = time.Minute
+	}
+
+	series := make([]models.MetricSeries, 0, len(names))
+	for _, name := range names {
+		buckets := make([][]float64, points)
+		for _, s := range history.samples[name] {
+			if s.Timestamp.Before(start) || !s.Timestamp.Before(end) {
+				continue
+			}
+			idx := int(s.Timestamp.Sub(start) / bucketDuration)
+			if idx >= points {
+				// The last bucket absorbs the remainder of the range.
+				idx = points - 1
+			}
+			buckets[idx] = append(buckets[idx], s.Value)
+		}
+		out := models.MetricSeries{
+			Name:   name,
+			Unit:   history.units[name],
+			Points: make([]*models.MetricPoint, points),
+		}
+		for i, values := range buckets {
+			out.Points[i] = summariseValues(values)
+		}
+		series = append(series, out)
+	}
+	return series, omitted
+}
+
+// summariseValues returns min, average, max and the nearest-rank 95th percentile of values.
+func summariseValues(values []float64) *models.MetricPoint {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return &models.MetricPoint{
+		Count: len(values),
+		Min:   values[0],
+		Avg:   sum / float64(len(values)),
+		Max:   values[len(values)-1],
+		P95:   values[rank],
+	}
+}
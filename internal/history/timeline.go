@@ -1,6 +1,7 @@
 package history
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -12,6 +13,7 @@ const (
 	// DefaultTimelinePoints controls how many dots we generate per service.
 	DefaultTimelinePoints = 80
 	maxDetailsPerPoint    = 4
+	maxDetailMessages     = 3
 )
 
 var warningStates = map[string]struct{}{
@@ -19,6 +21,12 @@ var warningStates = map[string]struct{}{
 	"deactivating": {},
 	"reloading":    {},
 	"maintenance":  {},
+	"warning":      {},
+	"restarted":    {},
+	"unconfirmed":  {},
+	"recovering":   {},
+	"pending":      {},
+	"starting":     {},
 }
 
 type sample struct {
@@ -26,6 +34,17 @@ type sample struct {
 	OK        bool
 	State     string
 	Error     string
+	Event     bool
+	Duration  time.Duration
+	// Run marks a finished job run; Duration is then the run time.
+	Run        bool
+	ExitStatus *int
+	// Messages holds journal lines explaining a failed sample.
+	Messages []string
+	// Excluded marks a blocked sample of a target with exclude_blocked.
+	Excluded bool
+	// Remediation summarises the action run in response to the sample.
+	Remediation string
 }
 
 // BuildServiceTimelines converts a history series into compact per-service timelines.
@@ -57,8 +76,10 @@ func BuildServiceTimelines(
 		}
 	}
 
+	excluded := make(map[string]bool)
 	for _, target := range targets {
 		registerName(target.ID, target.Name)
+		excluded[target.ID] = target.ExcludeBlocked
 	}
 
 	historyMap := make(map[string][]sample)
@@ -69,16 +90,38 @@ func BuildServiceTimelines(
 		registerName(id, name)
 		historyMap[id] = append(historyMap[id], s)
 	}
+	metricMap := make(map[string]*metricHistory)
 
 	for _, entry := range entries {
 		ts := entry.Timestamp
 		for _, check := range entry.Checks {
-			addSample(check.ID, check.Name, sample{
+			s := sample{
 				Timestamp: ts,
 				OK:        check.OK,
 				State:     check.State,
 				Error:     valueOrEmpty(check.Error),
-			})
+				Event:     entry.Event,
+				Excluded:  excluded[check.ID] && check.State == "blocked",
+			}
+			if run := check.Run; run != nil {
+				exitStatus := run.ExitStatus
+				s.Run = true
+				s.Duration = run.End.Sub(run.Start)
+				s.ExitStatus = &exitStatus
+			}
+			if journal := check.Journal; journal != nil && !check.OK {
+				s.Messages = journalMessages(journal.Lines)
+			}
+			if action := check.Remediation; action != nil {
+				s.Remediation = describeRemediation(*action)
+			}
+			addSample(check.ID, check.Name, s)
+			if check.ID != "" {
+				if metricMap[check.ID] == nil {
+					metricMap[check.ID] = &metricHistory{}
+				}
+				metricMap[check.ID].add(ts, check)
+			}
 		}
 	}
 	if latest != nil {
@@ -102,11 +145,15 @@ func BuildServiceTimelines(
 	for _, id := range ids {
 		name := nameMap[id]
 		timeline := buildTimeline(historyMap[id], start, end, points)
-		result = append(result, models.ServiceTimeline{
+		serviceTimeline := models.ServiceTimeline{
 			ServiceID:   id,
 			ServiceName: name,
 			Timeline:    timeline,
-		})
+		}
+		if metrics := metricMap[id]; metrics != nil {
+			serviceTimeline.Metrics, serviceTimeline.MetricsOmitted = buildMetricSeries(*metrics, start, end, points)
+		}
+		result = append(result, serviceTimeline)
 	}
 	return result
 }
@@ -121,12 +168,25 @@ func buildTimeline(samples []sample, start, end time.Time, points int) []models.
 			return samples[i].Timestamp.Before(samples[j].Timestamp)
 		})
 	}
+	// Transition events last exactly until the next observation of the service.
+	for i := 0; i+1 < len(samples); i++ {
+		if samples[i].Event && !samples[i].Run {
+			samples[i].Duration = samples[i+1].Timestamp.Sub(samples[i].Timestamp)
+		}
+	}
 
 	bucketDuration := end.Sub(start) / time.Duration(points)
 	if bucketDuration <= 0 {
 		bucketDuration = time.Minute
 	}
 
+	times := make([]time.Time, len(samples))
+	for i, s := range samples {
+		times[i] = s.Timestamp
+	}
+	// Targets checked less often than the bucket width keep their last state until the next sample.
+	gapThreshold := deriveGap(times)
+
 	cursor := 0
 	for i := 0; i < points; i++ {
 		bucketStart := start.Add(time.Duration(i) * bucketDuration)
@@ -135,6 +195,12 @@ func buildTimeline(samples []sample, start, end time.Time, points int) []models.
 			bucketEnd = end
 		}
 		bucketSamples, nextCursor := collectBucketSamples(samples, bucketStart, bucketEnd, cursor)
+		if len(bucketSamples) == 0 && nextCursor > 0 {
+			last := samples[nextCursor-1]
+			if last.Timestamp.Before(bucketStart) && bucketStart.Sub(last.Timestamp) <= gapThreshold {
+				bucketSamples = []sample{last}
+			}
+		}
 		cursor = nextCursor
 		class, label, details := evaluateBucket(bucketSamples)
 		output = append(output, models.TimelinePoint{
@@ -170,15 +236,19 @@ func collectBucketSamples(samples []sample, start, end time.Time, cursor int) ([
 	return chunk, j
 }
 
+// evaluateBucket classifies a bucket by its worst sample. Blocked samples rank below errors,
+// or below every other sample when they are excluded.
 func evaluateBucket(entries []sample) (className, label string, details []models.TimelineDetail) {
 	if len(entries) == 0 {
 		return "state-missing", "No data", nil
 	}
 	var (
-		hasError   bool
-		hasWarning bool
-		hasSuccess bool
-		hasMissing bool
+		hasError    bool
+		hasWarning  bool
+		hasSuccess  bool
+		hasMissing  bool
+		hasBlocked  bool
+		hasExcluded bool
 	)
 
 	details = make([]models.TimelineDetail, 0, maxDetailsPerPoint)
@@ -186,9 +256,23 @@ func evaluateBucket(entries []sample) (className, label string, details []models
 		state := strings.ToLower(entry.State)
 		errorState := !entry.OK && (state == "inactive" || state == "failed" || state == "degraded" || (state == "" && entry.Error != ""))
 		switch {
+		case entry.Run && entry.OK:
+			// Successful job runs are listed even in operational buckets.
+			hasSuccess = true
+			details = appendDetail(details, entry)
 		case errorState:
 			hasError = true
 			details = appendDetail(details, entry)
+		case state == "blocked":
+			if entry.Excluded {
+				hasExcluded = true
+			} else {
+				hasBlocked = true
+			}
+			details = appendDetail(details, entry)
+		case entry.OK && isWarningState(state):
+			hasWarning = true
+			details = appendDetail(details, entry)
 		case entry.OK || state == "active" || state == "running":
 			hasSuccess = true
 		case state == "missing":
@@ -196,7 +280,9 @@ func evaluateBucket(entries []sample) (className, label string, details []models
 		case isWarningState(state):
 			hasWarning = true
 			details = appendDetail(details, entry)
-		case state == "" || state == "unknown":
+		case state == "" || (state == "unknown" && entry.Error == ""):
+			// Only a sample without any outcome is missing data; a failed check that could not
+			// tell the state (Nagios UNKNOWN, timeouts, unreachable APIs) is an error.
 			hasMissing = true
 		default:
 			if entry.OK {
@@ -211,12 +297,16 @@ func evaluateBucket(entries []sample) (className, label string, details []models
 	switch {
 	case hasError:
 		return "state-error", "Unavailable", details
+	case hasBlocked:
+		return "state-blocked", "Dependency down", details
 	case hasMissing:
 		return "state-missing", "No data", details
 	case hasWarning:
 		return "state-warning", "Transitioning", details
 	case hasSuccess:
-		return "state-success", "Operational", nil
+		return "state-success", "Operational", details
+	case hasExcluded:
+		return "state-blocked", "Dependency down", details
 	default:
 		return "state-missing", "No data", details
 	}
@@ -227,12 +317,33 @@ func appendDetail(details []models.TimelineDetail, entry sample) []models.Timeli
 		return details
 	}
 	return append(details, models.TimelineDetail{
-		Timestamp: entry.Timestamp,
-		State:     entry.State,
-		Error:     entry.Error,
+		Timestamp:       entry.Timestamp,
+		State:           entry.State,
+		Error:           entry.Error,
+		DurationSeconds: entry.Duration.Seconds(),
+		ExitStatus:      entry.ExitStatus,
+		Messages:        entry.Messages,
+		Remediation:     entry.Remediation,
 	})
 }
 
+// describeRemediation summarises a remediation action for timeline tooltips.
+func describeRemediation(action models.RemediationResult) string {
+	if action.OK {
+		return fmt.Sprintf("%s succeeded (attempt %d)", action.Action, action.Attempt)
+	}
+	return fmt.Sprintf("%s failed (attempt %d): %s", action.Action, action.Attempt, action.Error)
+}
+
+// journalMessages returns the newest journal messages, newest first.
+func journalMessages(lines []models.JournalLine) []string {
+	messages := make([]string, 0, maxDetailMessages)
+	for i := len(lines) - 1; i >= 0 && len(messages) < maxDetailMessages; i-- {
+		messages = append(messages, lines[i].Message)
+	}
+	return messages
+}
+
 func valueOrEmpty(ptr *string) string {
 	if ptr == nil {
 		return ""
@@ -245,7 +356,62 @@ func isWarningState(state string) bool {
 	return ok
 }
 
-// BuildConnectivityTimeline reduces connectivity samples into compact timeline points.
+// ProbeHistory holds the samples recorded by one connectivity probe.
+type ProbeHistory struct {
+	Probe   models.ConnectivityProbe
+	Samples []models.ConnectivityStatus
+}
+
+// GroupConnectivity splits samples per probe. Configured probes come first, in configuration
+// order and even without samples; probes that only appear in the samples (for example removed
+// from the configuration) follow sorted by id.
+func GroupConnectivity(entries []models.ConnectivityStatus, probes []models.ConnectivityProbe) []ProbeHistory {
+	groups := make([]ProbeHistory, 0, len(probes))
+	index := make(map[string]int, len(probes))
+	for _, probe := range probes {
+		if _, ok := index[probe.ID]; ok {
+			continue
+		}
+		index[probe.ID] = len(groups)
+		groups = append(groups, ProbeHistory{Probe: probe})
+	}
+	configured := len(groups)
+	for _, entry := range entries {
+		id := entry.ProbeID()
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, ProbeHistory{Probe: models.ConnectivityProbe{ID: id, Name: id, Target: entry.Target}})
+		}
+		groups[i].Samples = append(groups[i].Samples, entry)
+	}
+	extra := groups[configured:]
+	sort.Slice(extra, func(i, j int) bool {
+		return extra[i].Probe.ID < extra[j].Probe.ID
+	})
+	return groups
+}
+
+// BuildConnectivityTimelines builds one timeline per connectivity probe.
+func BuildConnectivityTimelines(entries []models.ConnectivityStatus, probes []models.ConnectivityProbe, start, end time.Time, points int) []models.ConnectivityTimeline {
+	groups := GroupConnectivity(entries, probes)
+	if len(groups) == 0 {
+		return nil
+	}
+	result := make([]models.ConnectivityTimeline, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, models.ConnectivityTimeline{
+			ProbeID:   group.Probe.ID,
+			ProbeName: group.Probe.Name,
+			Target:    group.Probe.Target,
+			Timeline:  BuildConnectivityTimeline(group.Samples, start, end, points),
+		})
+	}
+	return result
+}
+
+// BuildConnectivityTimeline reduces the samples of a single probe into compact timeline points.
 func BuildConnectivityTimeline(entries []models.ConnectivityStatus, start, end time.Time, points int) []models.TimelinePoint {
 	if points <= 0 {
 		points = DefaultTimelinePoints
@@ -345,14 +511,24 @@ func BuildConnectivityTimeline(entries []models.ConnectivityStatus, start, end t
 }
 
 func deriveConnectivityGap(samples []models.ConnectivityStatus) time.Duration {
+	times := make([]time.Time, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.CheckedAt
+	}
+	return deriveGap(times)
+}
+
+// deriveGap returns how long a sample may be carried forward: twice the median spacing of
+// the sorted timestamps, bounded to [1m, 2h].
+func deriveGap(times []time.Time) time.Duration {
 	const defaultGap = 5 * time.Minute
-	if len(samples) < 2 {
+	if len(times) < 2 {
 		return defaultGap
 	}
-	diffs := make([]time.Duration, 0, len(samples)-1)
-	prev := samples[0].CheckedAt
-	for i := 1; i < len(samples); i++ {
-		curr := samples[i].CheckedAt
+	diffs := make([]time.Duration, 0, len(times)-1)
+	prev := times[0]
+	for i := 1; i < len(times); i++ {
+		curr := times[i]
 		if curr.After(prev) {
 			diffs = append(diffs, curr.Sub(prev))
 		}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"jobmonitor/internal/models"
+	"jobmonitor/internal/schedule"
 )
 
 // ServiceUptime summarises health of a monitored service.
@@ -17,6 +18,8 @@ type ServiceUptime struct {
 	Passing       int     `json:"passing"`
 	Failing       int     `json:"failing"`
 	Missing       int     `json:"missing_slots"`
+	Blocked       int     `json:"blocked_slots,omitempty"`
+	Transitions   int     `json:"transitions,omitempty"`
 	LastState     string  `json:"last_state,omitempty"`
 	LastUpdated   string  `json:"last_updated,omitempty"`
 }
@@ -24,7 +27,11 @@ type ServiceUptime struct {
 // ComputeServiceUptime aggregates uptime statistics per service from history entries.
 // Entries should already be filtered to the desired time window [start, end].
 // Missing samples (based on interval) are treated as failing checks to approximate downtime
-// when the monitor or server was offline.
+// when the monitor or server was offline. Targets with their own interval or schedule are
+// tracked as separate groups, so entries holding only a subset of targets do not count as
+// missed slots for the others. Event entries recorded between samples split the sample slot
+// they fall into, so a short outage counts for its exact duration. Time a target spent blocked
+// by a failed dependency is left out entirely when the target sets exclude_blocked.
 func ComputeServiceUptime(
 	entries []models.StatusEntry,
 	start time.Time,
@@ -37,30 +44,42 @@ func ComputeServiceUptime(
 	}
 
 	type acc struct {
-		name      string
-		passing   int
-		failing   int
-		lastState string
-		lastTime  time.Time
+		name        string
+		group       *slotGroup
+		exclude     bool
+		points      []statePoint
+		transitions int
+		lastState   string
+		lastTime    time.Time
 	}
 
+	defaultGroup := &slotGroup{every: interval}
+	groups := map[string]*slotGroup{"": defaultGroup}
 	summary := make(map[string]*acc)
 	for _, target := range expectedTargets {
-		summary[target.ID] = &acc{name: target.Name}
+		summary[target.ID] = &acc{name: target.Name, group: groupFor(groups, target, interval), exclude: target.ExcludeBlocked}
 	}
 
 	// ensure entries sorted? assume chronological.
 	for _, entry := range entries {
+		counted := make(map[*slotGroup]bool)
 		for _, check := range entry.Checks {
 			target := summary[check.ID]
 			if target == nil {
-				target = &acc{name: check.Name}
+				target = &acc{name: check.Name, group: defaultGroup}
 				summary[check.ID] = target
 			}
-			if check.OK {
-				target.passing++
-			} else {
-				target.failing++
+			target.points = append(target.points, statePoint{
+				at:       entry.Timestamp,
+				ok:       check.OK,
+				event:    entry.Event,
+				excluded: target.exclude && check.State == "blocked",
+			})
+			if entry.Event {
+				target.transitions++
+			} else if !counted[target.group] {
+				counted[target.group] = true
+				target.group.samples++
 			}
 			if check.State != "" {
 				target.lastState = check.State
@@ -73,22 +92,8 @@ func ComputeServiceUptime(
 		return nil
 	}
 
-	expectedSlots := 0
-	if interval > 0 {
-		duration := end.Sub(start)
-		if duration < 0 {
-			duration = 0
-		}
-		expectedSlots = int(math.Ceil(float64(duration) / float64(interval)))
-		// include final slot at end boundary
-		if expectedSlots < len(entries) {
-			expectedSlots = len(entries)
-		}
-	}
-
-	missingSlots := 0
-	if expectedSlots > len(entries) {
-		missingSlots = expectedSlots - len(entries)
+	for _, group := range groups {
+		group.missing = group.missingSlots(start, end)
 	}
 
 	keys := make([]string, 0, len(summary))
@@ -100,22 +105,26 @@ func ComputeServiceUptime(
 	results := make([]ServiceUptime, 0, len(keys))
 	for _, id := range keys {
 		data := summary[id]
-		data.failing += missingSlots
-
-		total := data.passing + data.failing
+		missingSlots := data.group.missing
+		passing, checks, blocked := weighSlots(data.points, data.group.every, end)
+		total := checks + float64(missingSlots)
 		uptime := 0.0
 		if total > 0 {
-			uptime = float64(data.passing) / float64(total) * 100
+			uptime = passing / total * 100
 		}
 
+		passingCount := int(math.Round(passing))
+		totalCount := int(math.Round(checks)) + missingSlots
 		result := ServiceUptime{
 			ID:            id,
 			Name:          data.name,
 			UptimePercent: round2(uptime),
-			TotalChecks:   total,
-			Passing:       data.passing,
-			Failing:       data.failing,
+			TotalChecks:   totalCount,
+			Passing:       passingCount,
+			Failing:       totalCount - passingCount,
 			Missing:       missingSlots,
+			Blocked:       int(math.Round(blocked)),
+			Transitions:   data.transitions,
 			LastState:     data.lastState,
 		}
 		if !data.lastTime.IsZero() {
@@ -126,6 +135,133 @@ func ComputeServiceUptime(
 	return results
 }
 
+// slotGroup collects targets that share a check interval or schedule.
+type slotGroup struct {
+	every   time.Duration
+	cron    *schedule.Cron
+	samples int
+	missing int
+}
+
+func groupFor(groups map[string]*slotGroup, target models.Target, interval time.Duration) *slotGroup {
+	key := ""
+	group := &slotGroup{every: interval}
+	switch {
+	case target.Schedule != "":
+		cron, err := schedule.Parse(target.Schedule)
+		if err != nil {
+			return groups[""]
+		}
+		key = "cron:" + target.Schedule
+		group = &slotGroup{cron: cron}
+	case target.IntervalSeconds > 0:
+		every := time.Duration(target.IntervalSeconds) * time.Second
+		key = "every:" + every.String()
+		group = &slotGroup{every: every}
+	}
+	if existing, ok := groups[key]; ok {
+		return existing
+	}
+	groups[key] = group
+	return group
+}
+
+// missingSlots compares the samples recorded for the group with the runs expected in the window.
+func (g *slotGroup) missingSlots(start, end time.Time) int {
+	expected := 0
+	switch {
+	case g.cron != nil:
+		expected = g.cron.Count(start, end)
+	case g.every > 0:
+		duration := end.Sub(start)
+		if duration < 0 {
+			duration = 0
+		}
+		expected = int(math.Ceil(float64(duration) / float64(g.every)))
+	}
+	// include final slot at end boundary
+	if expected < g.samples {
+		return 0
+	}
+	return expected - g.samples
+}
+
+type statePoint struct {
+	at    time.Time
+	ok    bool
+	event bool
+	// excluded marks a blocked state that counts neither as passing nor as failing.
+	excluded bool
+}
+
+// weighSlots returns the passing weight and number of regular samples for a target. Each
+// regular sample covers the slot up to the next sample (at most one interval); events inside
+// that slot switch the state for the remainder of the slot. Excluded parts of a slot are
+// returned as blocked instead of being counted as samples.
+func weighSlots(points []statePoint, interval time.Duration, end time.Time) (passing, samples, blocked float64) {
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].at.Before(points[j].at)
+	})
+
+	for i, point := range points {
+		if point.event {
+			continue
+		}
+
+		slotEnd := end
+		if interval > 0 {
+			slotEnd = point.at.Add(interval)
+		}
+		next := i + 1
+		for ; next < len(points); next++ {
+			if !points[next].event {
+				if points[next].at.Before(slotEnd) {
+					slotEnd = points[next].at
+				}
+				break
+			}
+		}
+
+		slot := slotEnd.Sub(point.at)
+		if slot <= 0 {
+			switch {
+			case point.excluded:
+				blocked++
+			case point.ok:
+				passing++
+				samples++
+			default:
+				samples++
+			}
+			continue
+		}
+
+		var up, skipped time.Duration
+		cursor := point.at
+		state := point
+		for j := i + 1; j < next && points[j].at.Before(slotEnd); j++ {
+			switch {
+			case state.excluded:
+				skipped += points[j].at.Sub(cursor)
+			case state.ok:
+				up += points[j].at.Sub(cursor)
+			}
+			cursor = points[j].at
+			state = points[j]
+		}
+		switch {
+		case state.excluded:
+			skipped += slotEnd.Sub(cursor)
+		case state.ok:
+			up += slotEnd.Sub(cursor)
+		}
+		passing += float64(up) / float64(slot)
+		samples += float64(slot-skipped) / float64(slot)
+		blocked += float64(skipped) / float64(slot)
+	}
+	return passing, samples, blocked
+}
+
 func round2(v float64) float64 {
 	return math.Round(v*100) / 100
 }
@@ -2,11 +2,39 @@ package models
 
 import "time"
 
+// DefaultConnectivityProbeID identifies the probe configured through monitor_dns. Samples recorded
+// before probes were named belong to it as well.
+const DefaultConnectivityProbeID = "dns"
+
+// ConnectivityProbe describes a named connectivity check such as the gateway, an upstream
+// resolver, an internet endpoint or a VPN peer.
+type ConnectivityProbe struct {
+	ID              string     `yaml:"id" json:"id"`
+	Name            string     `yaml:"name" json:"name"`
+	Type            string     `yaml:"type" json:"type"`
+	Target          string     `yaml:"target" json:"target"`
+	IntervalSeconds int        `yaml:"interval_seconds" json:"interval_seconds,omitempty"`
+	TimeoutSeconds  int        `yaml:"timeout_seconds" json:"timeout_seconds,omitempty"`
+	Queries         []DNSQuery `yaml:"queries" json:"queries,omitempty"`
+}
+
 // ConnectivityStatus captures the outcome of a connectivity probe.
 type ConnectivityStatus struct {
+	Probe     string    `json:"probe,omitempty"`
 	Target    string    `json:"target"`
 	OK        bool      `json:"ok"`
 	LatencyMs int64     `json:"latency_ms"`
+	Query     string    `json:"query,omitempty"`
+	Rcode     string    `json:"rcode,omitempty"`
+	Answers   int       `json:"answers,omitempty"`
 	Error     string    `json:"error,omitempty"`
 	CheckedAt time.Time `json:"checked_at"`
 }
+
+// ProbeID returns the probe that recorded the sample.
+func (s ConnectivityStatus) ProbeID() string {
+	if s.Probe == "" {
+		return DefaultConnectivityProbeID
+	}
+	return s.Probe
+}
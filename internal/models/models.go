@@ -1,30 +1,359 @@
 package models
 
 import (
+	"strconv"
+	"strings"
 	"time"
 )
 
-// Target defines a monitored systemd service.
+// Target types supported by the monitor.
+const (
+	TargetTypeSystemd   = "systemd"
+	TargetTypeHTTP      = "http"
+	TargetTypeTCP       = "tcp"
+	TargetTypeUDP       = "udp"
+	TargetTypeCommand   = "command"
+	TargetTypeTLS       = "tls"
+	TargetTypeDNS       = "dns"
+	TargetTypeHeartbeat = "heartbeat"
+	TargetTypeFile      = "file"
+	TargetTypeContainer = "container"
+	TargetTypeResource  = "resource"
+	TargetTypeProcess   = "process"
+)
+
+// Host resources measured by resource targets.
+const (
+	ResourceDisk   = "disk"
+	ResourceInodes = "inodes"
+	ResourceMemory = "memory"
+	ResourceSwap   = "swap"
+	ResourceLoad   = "load"
+	ResourcePSI    = "psi"
+)
+
+// Remediation actions a target can run when it keeps failing.
+const (
+	RemediationRestart = "restart"
+	RemediationCommand = "command"
+)
+
+// Systemd unit kinds selectable via a systemd target's kind.
+const (
+	UnitKindService = "service"
+	UnitKindOneshot = "oneshot"
+	UnitKindTimer   = "timer"
+)
+
+// Target defines a monitored service.
 type Target struct {
-	ID             string `yaml:"id" json:"id"`
-	Name           string `yaml:"name" json:"name"`
-	Service        string `yaml:"service" json:"service"`
-	URL            string `yaml:"url" json:"url,omitempty"`
-	TimeoutSeconds int    `yaml:"timeout_seconds" json:"timeout_seconds"`
-	UseSudo        bool   `yaml:"use_sudo" json:"use_sudo"`
+	ID                string              `yaml:"id" json:"id"`
+	Name              string              `yaml:"name" json:"name"`
+	Type              string              `yaml:"type" json:"type,omitempty"`
+	Service           string              `yaml:"service" json:"service"`
+	Kind              string              `yaml:"kind" json:"kind,omitempty"`
+	Timer             string              `yaml:"timer" json:"timer,omitempty"`
+	URL               string              `yaml:"url" json:"url,omitempty"`
+	Host              string              `yaml:"host" json:"host,omitempty"`
+	Port              int                 `yaml:"port" json:"port,omitempty"`
+	Command           string              `yaml:"command" json:"command,omitempty"`
+	Path              string              `yaml:"path" json:"path,omitempty"`
+	Args              []string            `yaml:"args" json:"-"`
+	TimeoutSeconds    int                 `yaml:"timeout_seconds" json:"timeout_seconds"`
+	WatchSeconds      int                 `yaml:"watch_seconds" json:"watch_seconds,omitempty"`
+	IntervalSeconds   int                 `yaml:"interval_seconds" json:"interval_seconds,omitempty"`
+	Schedule          string              `yaml:"schedule" json:"schedule,omitempty"`
+	Retries           int                 `yaml:"retries" json:"retries,omitempty"`
+	RetryDelaySeconds int                 `yaml:"retry_delay_seconds" json:"retry_delay_seconds,omitempty"`
+	FailAfter         int                 `yaml:"fail_after" json:"fail_after,omitempty"`
+	RecoverAfter      int                 `yaml:"recover_after" json:"recover_after,omitempty"`
+	UseSudo           bool                `yaml:"use_sudo" json:"use_sudo"`
+	DependsOn         []string            `yaml:"depends_on" json:"depends_on,omitempty"`
+	ExcludeBlocked    bool                `yaml:"exclude_blocked" json:"exclude_blocked,omitempty"`
+	HTTP              *HTTPOptions        `yaml:"http" json:"http,omitempty"`
+	UDP               *UDPOptions         `yaml:"udp" json:"udp,omitempty"`
+	TLS               *TLSOptions         `yaml:"tls" json:"tls,omitempty"`
+	DNS               *DNSQuery           `yaml:"dns" json:"dns,omitempty"`
+	Heartbeat         *HeartbeatOptions   `yaml:"heartbeat" json:"heartbeat,omitempty"`
+	File              *FileOptions        `yaml:"file" json:"file,omitempty"`
+	Journal           *JournalOptions     `yaml:"journal" json:"journal,omitempty"`
+	SSH               *SSHOptions         `yaml:"ssh" json:"ssh,omitempty"`
+	Container         *ContainerOptions   `yaml:"container" json:"container,omitempty"`
+	Resource          *ResourceOptions    `yaml:"resource" json:"resource,omitempty"`
+	Process           *ProcessOptions     `yaml:"process" json:"process,omitempty"`
+	Remediation       *RemediationOptions `yaml:"remediation" json:"remediation,omitempty"`
+	// Options holds free-form settings for target types registered by other packages.
+	Options map[string]interface{} `yaml:"options" json:"-"`
+}
+
+// HTTPOptions configures a request made by an http target against its URL.
+type HTTPOptions struct {
+	Method         string            `yaml:"method" json:"method,omitempty"`
+	Headers        map[string]string `yaml:"headers" json:"-"`
+	ExpectedStatus []int             `yaml:"expected_status" json:"expected_status,omitempty"`
+	BodyContains   string            `yaml:"body_contains" json:"body_contains,omitempty"`
+	BodyRegex      string            `yaml:"body_regex" json:"body_regex,omitempty"`
+}
+
+// UDPOptions configures the datagram exchanged by a udp target.
+type UDPOptions struct {
+	Payload        string `yaml:"payload" json:"payload,omitempty"`
+	ExpectReply    bool   `yaml:"expect_reply" json:"expect_reply,omitempty"`
+	ExpectContains string `yaml:"expect_contains" json:"expect_contains,omitempty"`
+}
+
+// TLSOptions configures certificate inspection for tls targets and https http targets.
+type TLSOptions struct {
+	ServerName string `yaml:"server_name" json:"server_name,omitempty"`
+	WarnDays   int    `yaml:"warn_days" json:"warn_days,omitempty"`
+}
+
+// JournalOptions enables a scan of a systemd target's journal alongside its unit check. Lines at
+// or above Priority, or matching Pattern, within the last WindowSeconds are counted; a nil
+// threshold is not applied.
+type JournalOptions struct {
+	WindowSeconds int    `yaml:"window_seconds" json:"window_seconds,omitempty"`
+	Priority      string `yaml:"priority" json:"priority,omitempty"`
+	Pattern       string `yaml:"pattern" json:"pattern,omitempty"`
+	WarnAbove     *int   `yaml:"warn_above" json:"warn_above,omitempty"`
+	ErrorAbove    *int   `yaml:"error_above" json:"error_above,omitempty"`
+}
+
+// journalPriorities maps syslog priority names to their numeric levels.
+var journalPriorities = map[string]int{
+	"emerg":   0,
+	"alert":   1,
+	"crit":    2,
+	"err":     3,
+	"warning": 4,
+	"notice":  5,
+	"info":    6,
+	"debug":   7,
+}
+
+// ParseJournalPriority converts a syslog priority name or number (0-7) to its level.
+func ParseJournalPriority(value string) (int, bool) {
+	if level, ok := journalPriorities[strings.ToLower(value)]; ok {
+		return level, true
+	}
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 0 || level > 7 {
+		return 0, false
+	}
+	return level, true
+}
+
+// ResourceOptions selects a host resource and the thresholds it is compared against. Disk,
+// inode, memory and swap usage and PSI stall times are percentages; load is the load average
+// per CPU. Window picks the load average (1, 5 or 15 minutes) or PSI average (10, 60 or 300
+// seconds); Pressure picks the PSI resource (cpu, memory or io).
+type ResourceOptions struct {
+	Kind     string  `yaml:"kind" json:"kind"`
+	Pressure string  `yaml:"pressure" json:"pressure,omitempty"`
+	Window   int     `yaml:"window" json:"window,omitempty"`
+	Warning  float64 `yaml:"warning" json:"warning,omitempty"`
+	Critical float64 `yaml:"critical" json:"critical,omitempty"`
+}
+
+// ProcessOptions selects processes by name, command line regex and/or pidfile; a process must
+// satisfy every criterion that is set. RSS and CPU limits apply to all matched processes combined.
+type ProcessOptions struct {
+	Name          string  `yaml:"name" json:"name,omitempty"`
+	Cmdline       string  `yaml:"cmdline" json:"cmdline,omitempty"`
+	Pidfile       string  `yaml:"pidfile" json:"pidfile,omitempty"`
+	MinInstances  int     `yaml:"min_instances" json:"min_instances,omitempty"`
+	MaxInstances  int     `yaml:"max_instances" json:"max_instances,omitempty"`
+	MaxRSSMB      float64 `yaml:"max_rss_mb" json:"max_rss_mb,omitempty"`
+	MaxCPUPercent float64 `yaml:"max_cpu_percent" json:"max_cpu_percent,omitempty"`
+}
+
+// RemediationOptions configures an action run after AfterFailures consecutive confirmed failures:
+// restarting the target's systemd unit or running Command with Args. Actions are at least
+// CooldownSeconds apart and limited to MaxPerHour within any hour.
+type RemediationOptions struct {
+	Action          string   `yaml:"action" json:"action"`
+	Command         string   `yaml:"command" json:"command,omitempty"`
+	Args            []string `yaml:"args" json:"-"`
+	AfterFailures   int      `yaml:"after_failures" json:"after_failures"`
+	CooldownSeconds int      `yaml:"cooldown_seconds" json:"cooldown_seconds"`
+	MaxPerHour      int      `yaml:"max_per_hour" json:"max_per_hour"`
+	TimeoutSeconds  int      `yaml:"timeout_seconds" json:"timeout_seconds"`
+}
+
+// SSHOptions configures how systemd targets on another host (Target.Host) are reached. The
+// OpenSSH client runs non-interactively, so the key must not need a passphrase prompt.
+type SSHOptions struct {
+	User           string `yaml:"user" json:"user,omitempty"`
+	IdentityFile   string `yaml:"identity_file" json:"identity_file,omitempty"`
+	KnownHostsFile string `yaml:"known_hosts_file" json:"known_hosts_file,omitempty"`
+}
+
+// DefaultContainerSocket is the Engine API socket used when a container target does not set one.
+const DefaultContainerSocket = "/var/run/docker.sock"
+
+// ContainerOptions names a container and the Docker or Podman Engine API socket that manages it.
+type ContainerOptions struct {
+	Name   string `yaml:"name" json:"name"`
+	Socket string `yaml:"socket" json:"socket,omitempty"`
+}
+
+// HeartbeatOptions configures how often a push-style heartbeat target expects pings.
+type HeartbeatOptions struct {
+	PeriodSeconds     int `yaml:"period_seconds" json:"period_seconds"`
+	GraceSeconds      int `yaml:"grace_seconds" json:"grace_seconds,omitempty"`
+	MaxRuntimeSeconds int `yaml:"max_runtime_seconds" json:"max_runtime_seconds,omitempty"`
+	// Token must accompany every ping. It is never serialised to API responses.
+	Token string `yaml:"token" json:"-"`
+}
+
+// FileOptions lists the assertions a file target makes about its path. Contains and NotContains
+// are regular expressions applied to the last TailLines lines.
+type FileOptions struct {
+	MaxAgeSeconds int    `yaml:"max_age_seconds" json:"max_age_seconds,omitempty"`
+	MinBytes      int64  `yaml:"min_bytes" json:"min_bytes,omitempty"`
+	MaxBytes      int64  `yaml:"max_bytes" json:"max_bytes,omitempty"`
+	Contains      string `yaml:"contains" json:"contains,omitempty"`
+	NotContains   string `yaml:"not_contains" json:"not_contains,omitempty"`
+	TailLines     int    `yaml:"tail_lines" json:"tail_lines,omitempty"`
+}
+
+// DNSQuery describes a DNS lookup and the answers it must return.
+type DNSQuery struct {
+	Name   string   `yaml:"name" json:"name"`
+	Type   string   `yaml:"type" json:"type,omitempty"`
+	Expect []string `yaml:"expect" json:"expect,omitempty"`
 }
 
 // CheckResult captures the outcome of a single target check.
 type CheckResult struct {
-	ID    string  `json:"id"`
-	Name  string  `json:"name"`
-	OK    bool    `json:"ok"`
-	State string  `json:"state,omitempty"`
-	Error *string `json:"error,omitempty"`
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	OK         bool              `json:"ok"`
+	State      string            `json:"state,omitempty"`
+	Error      *string           `json:"error,omitempty"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	DurationMs int64             `json:"duration_ms,omitempty"`
+	LatencyMs  int64             `json:"latency_ms,omitempty"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Output     string            `json:"output,omitempty"`
+	Metrics    map[string]Metric `json:"metrics,omitempty"`
+	Unit       *UnitStatus       `json:"unit,omitempty"`
+	Attempts   []CheckAttempt    `json:"attempts,omitempty"`
+	TLS        *TLSInfo          `json:"tls,omitempty"`
+	Heartbeat  *HeartbeatInfo    `json:"heartbeat,omitempty"`
+	Run        *JobRun           `json:"run,omitempty"`
+	Journal    *JournalInfo      `json:"journal,omitempty"`
+	Container  *ContainerInfo    `json:"container,omitempty"`
+	// BlockedBy names the target or connectivity probe whose outage caused this failure
+	// (state "blocked"), following dependencies down to the root cause.
+	BlockedBy string `json:"blocked_by,omitempty"`
+	// Remediation describes the action run in response to this result, if any.
+	Remediation *RemediationResult `json:"remediation,omitempty"`
+}
+
+// RemediationResult records a remediation action and its outcome. Attempt counts the actions
+// run for the target within the last hour, including this one.
+type RemediationResult struct {
+	Action     string    `json:"action"`
+	Command    string    `json:"command"`
+	Attempt    int       `json:"attempt"`
+	OK         bool      `json:"ok"`
+	Output     string    `json:"output,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// JournalInfo summarises the journal lines counted for a target since Since. Lines holds the
+// newest of them.
+type JournalInfo struct {
+	Since time.Time     `json:"since"`
+	Count int           `json:"count"`
+	Lines []JournalLine `json:"lines,omitempty"`
+}
+
+// JournalLine is a single journal message.
+type JournalLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Priority  int       `json:"priority"`
+	Message   string    `json:"message"`
+}
+
+// JobRun describes a finished run of a oneshot or timer-driven unit. It is only set on the
+// event entries recorded for each run.
+type JobRun struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Result     string    `json:"result,omitempty"`
+	ExitStatus int       `json:"exit_status"`
+}
+
+// ContainerInfo describes a container as reported by the Engine API.
+type ContainerInfo struct {
+	ID           string     `json:"id,omitempty"`
+	Image        string     `json:"image,omitempty"`
+	Status       string     `json:"status"`
+	Health       string     `json:"health,omitempty"`
+	RestartCount int        `json:"restart_count"`
+	ExitCode     int        `json:"exit_code"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
+
+// HeartbeatInfo describes the pings received for a heartbeat target.
+type HeartbeatInfo struct {
+	LastStart *time.Time `json:"last_start,omitempty"`
+	LastPing  *time.Time `json:"last_ping,omitempty"`
+	ExitCode  *int       `json:"exit_code,omitempty"`
+}
+
+// TLSInfo describes the certificate chain served by an endpoint.
+type TLSInfo struct {
+	Subject     string    `json:"subject,omitempty"`
+	Issuer      string    `json:"issuer,omitempty"`
+	DNSNames    []string  `json:"dns_names,omitempty"`
+	NotAfter    time.Time `json:"not_after"`
+	DaysLeft    int       `json:"days_left"`
+	ChainValid  bool      `json:"chain_valid"`
+	VerifyError string    `json:"verify_error,omitempty"`
+}
+
+// CheckAttempt records one raw try of a check when retries or confirmation are involved.
+type CheckAttempt struct {
+	OK         bool   `json:"ok"`
+	State      string `json:"state,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+}
+
+// UnitStatus carries systemd unit properties collected alongside a check.
+type UnitStatus struct {
+	ActiveState  string     `json:"active_state,omitempty"`
+	SubState     string     `json:"sub_state,omitempty"`
+	Result       string     `json:"result,omitempty"`
+	NRestarts    int        `json:"n_restarts"`
+	MainPID      int        `json:"main_pid,omitempty"`
+	MainStatus   int        `json:"main_status"`
+	ActiveSince  *time.Time `json:"active_since,omitempty"`
+	MemoryBytes  uint64     `json:"memory_bytes,omitempty"`
+	CPUUsageNSec uint64     `json:"cpu_usage_nsec,omitempty"`
+	LastRunStart *time.Time `json:"last_run_start,omitempty"`
+	LastRunEnd   *time.Time `json:"last_run_end,omitempty"`
+	TimerState   string     `json:"timer_state,omitempty"`
+	LastTrigger  *time.Time `json:"last_trigger,omitempty"`
+	NextElapse   *time.Time `json:"next_elapse,omitempty"`
+}
+
+// Metric is a numeric measurement attached to a check result.
+type Metric struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
 }
 
-// StatusEntry stores the results of all checks at a moment in time.
+// StatusEntry stores the results of all checks at a moment in time. Event entries record a
+// state transition observed between regular samples and hold only the targets that changed.
 type StatusEntry struct {
 	Timestamp time.Time     `json:"timestamp"`
+	Event     bool          `json:"event,omitempty"`
 	Checks    []CheckResult `json:"checks"`
 }
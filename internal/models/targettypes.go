@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// TargetValidator checks the configuration of a target whose type was registered outside the
+// config package.
+type TargetValidator func(Target) error
+
+// builtinTargetTypes are validated by the config package itself and cannot be registered.
+var builtinTargetTypes = map[string]bool{
+	TargetTypeSystemd:   true,
+	TargetTypeHTTP:      true,
+	TargetTypeTCP:       true,
+	TargetTypeUDP:       true,
+	TargetTypeCommand:   true,
+	TargetTypeTLS:       true,
+	TargetTypeDNS:       true,
+	TargetTypeHeartbeat: true,
+	TargetTypeFile:      true,
+	TargetTypeContainer: true,
+	TargetTypeResource:  true,
+	TargetTypeProcess:   true,
+}
+
+// BuiltinTargetTypes returns the target types validated by the config package, sorted by name.
+func BuiltinTargetTypes() []string {
+	types := make([]string, 0, len(builtinTargetTypes))
+	for name := range builtinTargetTypes {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+var (
+	targetTypesMu sync.RWMutex
+	targetTypes   = make(map[string]TargetValidator)
+)
+
+// RegisterTargetType makes an additional target type known to configuration validation.
+// validate may be nil.
+func RegisterTargetType(name string, validate TargetValidator) error {
+	targetTypesMu.Lock()
+	defer targetTypesMu.Unlock()
+
+	if name == "" {
+		return fmt.Errorf("target type name must not be empty")
+	}
+	if _, taken := targetTypes[name]; taken || builtinTargetTypes[name] {
+		return fmt.Errorf("target type %q is already registered", name)
+	}
+	targetTypes[name] = validate
+	return nil
+}
+
+// LookupTargetType returns the validator of a registered target type.
+func LookupTargetType(name string) (TargetValidator, bool) {
+	targetTypesMu.RLock()
+	defer targetTypesMu.RUnlock()
+
+	validate, ok := targetTypes[name]
+	return validate, ok
+}
@@ -13,9 +13,13 @@ type TimelinePoint struct {
 
 // TimelineDetail carries extra information for problematic buckets.
 type TimelineDetail struct {
-	Timestamp time.Time `json:"timestamp"`
-	State     string    `json:"state,omitempty"`
-	Error     string    `json:"error,omitempty"`
+	Timestamp       time.Time `json:"timestamp"`
+	State           string    `json:"state,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	DurationSeconds float64   `json:"duration_seconds,omitempty"`
+	ExitStatus      *int      `json:"exit_status,omitempty"`
+	Messages        []string  `json:"messages,omitempty"`
+	Remediation     string    `json:"remediation,omitempty"`
 }
 
 // ServiceTimeline aggregates timeline points for a single service.
@@ -23,4 +27,33 @@ type ServiceTimeline struct {
 	ServiceID   string          `json:"service_id"`
 	ServiceName string          `json:"service_name"`
 	Timeline    []TimelinePoint `json:"timeline"`
+	Metrics     []MetricSeries  `json:"metrics,omitempty"`
+	// MetricsOmitted counts the metrics left out of Metrics because of the per-service cap.
+	MetricsOmitted int `json:"metrics_omitted,omitempty"`
+}
+
+// MetricSeries aggregates one metric of a service over the buckets of its timeline; Points
+// line up with the timeline points.
+type MetricSeries struct {
+	Name   string         `json:"name"`
+	Unit   string         `json:"unit,omitempty"`
+	Points []*MetricPoint `json:"points"`
+}
+
+// MetricPoint summarises the values recorded within a timeline bucket. Buckets without values
+// are nil.
+type MetricPoint struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Avg   float64 `json:"avg"`
+	Max   float64 `json:"max"`
+	P95   float64 `json:"p95"`
+}
+
+// ConnectivityTimeline aggregates timeline points for a single connectivity probe.
+type ConnectivityTimeline struct {
+	ProbeID   string          `json:"probe_id"`
+	ProbeName string          `json:"probe_name"`
+	Target    string          `json:"target,omitempty"`
+	Timeline  []TimelinePoint `json:"timeline"`
 }
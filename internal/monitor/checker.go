@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"jobmonitor/internal/models"
+)
+
+// Checker checks a single target. Results need ID, Name, OK, State and, when failing, Error;
+// the monitor stamps CheckedAt and DurationMs and applies retries and confirmation policies.
+// The context carries the target's timeout.
+type Checker interface {
+	Check(ctx context.Context, target models.Target) models.CheckResult
+}
+
+// CheckerFunc adapts an ordinary function to the Checker interface.
+type CheckerFunc func(ctx context.Context, target models.Target) models.CheckResult
+
+// Check calls f(ctx, target).
+func (f CheckerFunc) Check(ctx context.Context, target models.Target) models.CheckResult {
+	return f(ctx, target)
+}
+
+// TargetValidator is implemented by registered checkers that validate their targets' settings
+// (typically Target.Options) when the configuration is loaded.
+type TargetValidator interface {
+	Validate(target models.Target) error
+}
+
+var (
+	checkersMu sync.RWMutex
+	checkers   = make(map[string]Checker)
+)
+
+// RegisterChecker makes checker responsible for targets with the given type. It is meant to be
+// called from the init function of the package providing the checker, and panics when the type
+// is empty, built in or already registered. Registered types pass config validation; checkers
+// implementing TargetValidator also validate their targets.
+func RegisterChecker(targetType string, checker Checker) {
+	if checker == nil {
+		panic("monitor: RegisterChecker checker is nil")
+	}
+	var validate models.TargetValidator
+	if v, ok := checker.(TargetValidator); ok {
+		validate = v.Validate
+	}
+	checkersMu.Lock()
+	defer checkersMu.Unlock()
+	if err := models.RegisterTargetType(targetType, validate); err != nil {
+		panic("monitor: " + err.Error())
+	}
+	checkers[targetType] = checker
+}
+
+// builtinCheckers returns the checkers for the target types this package implements.
+func (m *Monitor) builtinCheckers() map[string]Checker {
+	return map[string]Checker{
+		models.TargetTypeSystemd:   CheckerFunc(m.checkUnit),
+		models.TargetTypeHTTP:      CheckerFunc(m.checkHTTP),
+		models.TargetTypeTCP:       CheckerFunc(m.checkPort),
+		models.TargetTypeUDP:       CheckerFunc(m.checkPort),
+		models.TargetTypeCommand:   CheckerFunc(m.checkCommand),
+		models.TargetTypeTLS:       CheckerFunc(m.checkTLS),
+		models.TargetTypeDNS:       CheckerFunc(m.checkDNS),
+		models.TargetTypeHeartbeat: CheckerFunc(m.checkHeartbeat),
+		models.TargetTypeFile:      CheckerFunc(m.checkFile),
+		models.TargetTypeContainer: CheckerFunc(m.checkContainer),
+		models.TargetTypeResource:  CheckerFunc(m.checkResource),
+		models.TargetTypeProcess:   CheckerFunc(m.checkProcess),
+	}
+}
+
+// checker returns the checker for a target type; targets without a type are systemd units.
+func (m *Monitor) checker(targetType string) (Checker, bool) {
+	if targetType == "" {
+		targetType = models.TargetTypeSystemd
+	}
+	if checker, ok := m.checkers[targetType]; ok {
+		return checker, true
+	}
+	checkersMu.RLock()
+	defer checkersMu.RUnlock()
+	checker, ok := checkers[targetType]
+	return checker, ok
+}
+
+func (m *Monitor) checkTarget(ctx context.Context, target models.Target) models.CheckResult {
+	checker, ok := m.checker(target.Type)
+	if !ok {
+		res := models.CheckResult{ID: target.ID, Name: target.Name}
+		return failResult(res, "unknown", fmt.Sprintf("no checker for target type %q", target.Type))
+	}
+	res := checker.Check(ctx, target)
+	if res.ID == "" {
+		res.ID = target.ID
+	}
+	if res.Name == "" {
+		res.Name = target.Name
+	}
+	return res
+}
+
+// checkUnit checks a systemd unit and, when configured, its journal.
+func (m *Monitor) checkUnit(ctx context.Context, target models.Target) models.CheckResult {
+	res := m.checkSystemd(ctx, target)
+	if target.Journal != nil {
+		res = m.checkJournal(ctx, target, res)
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"jobmonitor/internal/models"
+)
+
+func TestBuiltinCheckersMatchTargetTypes(t *testing.T) {
+	m, _ := newTestMonitor(t, nil, &fakeRunner{})
+	builtin := m.builtinCheckers()
+	for _, name := range models.BuiltinTargetTypes() {
+		if _, ok := builtin[name]; !ok {
+			t.Errorf("built-in target type %q has no checker", name)
+		}
+	}
+	if got, want := len(builtin), len(models.BuiltinTargetTypes()); got != want {
+		t.Errorf("%d built-in checkers for %d built-in target types", got, want)
+	}
+}
+
+type validatingChecker struct{}
+
+func (validatingChecker) Check(_ context.Context, target models.Target) models.CheckResult {
+	mode, _ := target.Options["mode"].(string)
+	return models.CheckResult{OK: true, State: "custom:" + mode}
+}
+
+func (validatingChecker) Validate(target models.Target) error {
+	if mode, _ := target.Options["mode"].(string); mode == "" {
+		return errors.New("mode is required")
+	}
+	return nil
+}
+
+func init() {
+	RegisterChecker("test-dispatch", validatingChecker{})
+}
+
+func TestRegisterCheckerDispatch(t *testing.T) {
+	validate, ok := models.LookupTargetType("test-dispatch")
+	if !ok || validate == nil {
+		t.Fatalf("registered type has no validator")
+	}
+	if err := validate(models.Target{ID: "x"}); err == nil {
+		t.Errorf("validator accepted a target without mode")
+	}
+
+	target := models.Target{ID: "x", Name: "X", Type: "test-dispatch", Options: map[string]interface{}{"mode": "fast"}}
+	m, _ := newTestMonitor(t, []models.Target{target}, &fakeRunner{})
+	res := m.runCheck(context.Background(), target)
+	if !res.OK || res.State != "custom:fast" || res.ID != "x" || res.Name != "X" {
+		t.Fatalf("custom check = %+v, want OK custom:fast with the target's id and name", res)
+	}
+
+	res = m.runCheck(context.Background(), models.Target{ID: "y", Type: "test-unregistered"})
+	if res.OK || res.State != "unknown" {
+		t.Fatalf("unregistered type = %+v, want unknown failure", res)
+	}
+}
+
+func TestRegisterCheckerRejectsDuplicates(t *testing.T) {
+	for _, name := range []string{"test-dispatch", models.TargetTypeHTTP, ""} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterChecker(%q) did not panic", name)
+				}
+			}()
+			RegisterChecker(name, validatingChecker{})
+		}()
+	}
+}
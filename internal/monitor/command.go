@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"jobmonitor/internal/models"
+)
+
+// Nagios plugin exit codes.
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// perfdataPattern matches a single 'label'=value[UOM];warn;crit;min;max item.
+var perfdataPattern = regexp.MustCompile(`('[^']+'|[^\s=]+)=(-?[0-9.]+(?:[eE][-+]?[0-9]+)?)([^;\s]*)`)
+
+func (m *Monitor) checkCommand(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	stdout, stderr, err := m.runner.Run(ctx, target.Command, target.Args...)
+
+	output, perfdata := splitPluginOutput(string(stdout))
+	res.Output = output
+	res.Metrics = parsePerfdata(perfdata)
+
+	code := nagiosOK
+	if err != nil {
+		var exitErr exitCoder
+		switch {
+		case ctx.Err() != nil:
+			msg := fmt.Sprintf("command timed out: %v", ctx.Err())
+			return failResult(res, "unknown", msg)
+		case errors.As(err, &exitErr):
+			code = exitErr.ExitCode()
+		default:
+			return failResult(res, "unknown", err.Error())
+		}
+	}
+
+	msg := output
+	if msg == "" {
+		msg = strings.TrimSpace(string(stderr))
+	}
+	switch code {
+	case nagiosOK:
+		res.OK = true
+		res.State = "ok"
+		return res
+	case nagiosWarning:
+		return failResult(res, "warning", fallbackMessage(msg, "plugin returned WARNING"))
+	case nagiosCritical:
+		return failResult(res, "critical", fallbackMessage(msg, "plugin returned CRITICAL"))
+	case nagiosUnknown:
+		return failResult(res, "unknown", fallbackMessage(msg, "plugin returned UNKNOWN"))
+	default:
+		return failResult(res, "critical", fallbackMessage(msg, fmt.Sprintf("exit status %d", code)))
+	}
+}
+
+// splitPluginOutput returns the first line of plugin output and all perfdata text found after '|'.
+func splitPluginOutput(raw string) (string, string) {
+	lines := strings.Split(strings.TrimSpace(raw), "\n")
+	first, perf, _ := strings.Cut(lines[0], "|")
+	perfParts := []string{perf}
+	// Long output may carry additional perfdata after a '|' on a later line.
+	for _, line := range lines[1:] {
+		if _, extra, ok := strings.Cut(line, "|"); ok {
+			perfParts = append(perfParts, extra)
+		}
+	}
+	return strings.TrimSpace(first), strings.TrimSpace(strings.Join(perfParts, " "))
+}
+
+func parsePerfdata(raw string) map[string]models.Metric {
+	if raw == "" {
+		return nil
+	}
+	matches := perfdataPattern.FindAllStringSubmatch(raw, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	metrics := make(map[string]models.Metric, len(matches))
+	for _, match := range matches {
+		value, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		label := strings.Trim(match[1], "'")
+		metrics[label] = models.Metric{Value: value, Unit: match[3]}
+	}
+	return metrics
+}
+
+func fallbackMessage(msg, fallback string) string {
+	if msg == "" {
+		return fallback
+	}
+	return msg
+}
@@ -1,68 +1,70 @@
 package monitor
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"net"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	"jobmonitor/internal/config"
 	"jobmonitor/internal/models"
 	"jobmonitor/internal/storage"
 )
 
 // ConnectivitySource exposes connectivity probe results.
 type ConnectivitySource interface {
-	Latest() (models.ConnectivityStatus, bool)
+	Probes() []models.ConnectivityProbe
+	Latest() []models.ConnectivityStatus
 	History() []models.ConnectivityStatus
 	HistorySince(time.Time) []models.ConnectivityStatus
 }
 
-// ConnectivityMonitor periodically probes connectivity to a DNS endpoint.
+// ConnectivityMonitor periodically runs the configured connectivity probes. Every probe runs on
+// its own interval; all samples share one chronological history.
 type ConnectivityMonitor struct {
-	cfg        config.MonitorDNS
-	interval   time.Duration
+	probes     []models.ConnectivityProbe
 	maxHistory int
 	store      *storage.ConnectivityStorage
+	client     *http.Client
+	clock      Clock
 
 	mu      sync.RWMutex
-	latest  *models.ConnectivityStatus
+	latest  map[string]models.ConnectivityStatus
 	history []models.ConnectivityStatus
 
 	stopCh chan struct{}
 	doneCh chan struct{}
 }
 
-// NewConnectivityMonitor configures a new connectivity monitor.
-func NewConnectivityMonitor(cfg config.MonitorDNS, store *storage.ConnectivityStorage) *ConnectivityMonitor {
-	interval := time.Duration(cfg.IntervalSeconds) * time.Second
-	if interval <= 0 {
-		interval = 60 * time.Second
-	}
-
+// NewConnectivityMonitor configures a new connectivity monitor for the given probes.
+func NewConnectivityMonitor(probes []models.ConnectivityProbe, store *storage.ConnectivityStorage) *ConnectivityMonitor {
+	// Keep roughly 30 days of samples per probe.
+	const maxCap = 100000
 	historyCap := 2048
-	if cfg.Enabled {
-		slots := int((30 * 24 * time.Hour) / interval)
-		if slots < 0 {
-			slots = 0
-		}
-		slots += 128 // small buffer
-		if slots > historyCap {
-			historyCap = slots
-		}
-		const maxCap = 100000
-		if historyCap > maxCap {
-			historyCap = maxCap
-		}
+	slots := 0
+	for _, probe := range probes {
+		slots += int((30*24*time.Hour)/probeInterval(probe)) + 128 // small buffer
+	}
+	if slots > historyCap {
+		historyCap = slots
+	}
+	if historyCap > maxCap {
+		historyCap = maxCap
 	}
 
+	transport := newHTTPClient().Transport.(*http.Transport)
+	transport.DisableKeepAlives = true
+
 	monitor := &ConnectivityMonitor{
-		cfg:        cfg,
-		interval:   interval,
+		probes:     probes,
 		maxHistory: historyCap,
 		store:      store,
+		client:     &http.Client{Transport: transport},
+		clock:      systemClock{},
+		latest:     make(map[string]models.ConnectivityStatus),
 		stopCh:     make(chan struct{}),
 		doneCh:     make(chan struct{}),
 	}
@@ -70,16 +72,16 @@ func NewConnectivityMonitor(cfg config.MonitorDNS, store *storage.ConnectivitySt
 	return monitor
 }
 
-// Start launches the monitoring loop. If disabled, the monitor exits immediately.
+// Start launches the monitoring loops. Without probes the monitor exits immediately.
 func (m *ConnectivityMonitor) Start() {
-	if !m.cfg.Enabled {
+	if len(m.probes) == 0 {
 		close(m.doneCh)
 		return
 	}
 	go m.run()
 }
 
-// Stop requests the monitoring loop to terminate.
+// Stop requests the monitoring loops to terminate.
 func (m *ConnectivityMonitor) Stop() {
 	select {
 	case <-m.doneCh:
@@ -90,15 +92,25 @@ func (m *ConnectivityMonitor) Stop() {
 	<-m.doneCh
 }
 
-// Latest returns the most recent connectivity sample.
-func (m *ConnectivityMonitor) Latest() (models.ConnectivityStatus, bool) {
+// Probes returns the configured probes.
+func (m *ConnectivityMonitor) Probes() []models.ConnectivityProbe {
+	out := make([]models.ConnectivityProbe, len(m.probes))
+	copy(out, m.probes)
+	return out
+}
+
+// Latest returns the most recent sample of every configured probe, in configuration order.
+func (m *ConnectivityMonitor) Latest() []models.ConnectivityStatus {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if m.latest == nil {
-		return models.ConnectivityStatus{}, false
+	out := make([]models.ConnectivityStatus, 0, len(m.probes))
+	for _, probe := range m.probes {
+		if sample, ok := m.latest[probe.ID]; ok {
+			out = append(out, sample)
+		}
 	}
-	return *m.latest, true
+	return out
 }
 
 // History returns up to maxHistory previous connectivity samples.
@@ -143,59 +155,133 @@ func (m *ConnectivityMonitor) HistorySince(cutoff time.Time) []models.Connectivi
 func (m *ConnectivityMonitor) run() {
 	defer close(m.doneCh)
 
-	interval := m.interval
-	timeout := time.Duration(m.cfg.TimeoutSeconds) * time.Second
-	if timeout <= 0 {
-		timeout = 4 * time.Second
+	var wg sync.WaitGroup
+	for _, probe := range m.probes {
+		wg.Add(1)
+		go func(probe models.ConnectivityProbe) {
+			defer wg.Done()
+			m.runProbe(probe)
+		}(probe)
 	}
+	wg.Wait()
+}
 
-	m.probe(timeout)
+func (m *ConnectivityMonitor) runProbe(probe models.ConnectivityProbe) {
+	m.record(m.probe(probe))
 
-	ticker := time.NewTicker(interval)
+	ticker := time.NewTicker(probeInterval(probe))
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
-			m.probe(timeout)
+			m.record(m.probe(probe))
 		case <-m.stopCh:
 			return
 		}
 	}
 }
 
-func (m *ConnectivityMonitor) probe(timeout time.Duration) {
-	target := strings.TrimSpace(m.cfg.Target)
-	if target == "" {
-		target = "1.1.1.1"
+func probeInterval(probe models.ConnectivityProbe) time.Duration {
+	interval := time.Duration(probe.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+	return interval
+}
+
+// probe runs a single probe and returns its sample.
+func (m *ConnectivityMonitor) probe(probe models.ConnectivityProbe) models.ConnectivityStatus {
+	timeout := time.Duration(probe.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 4 * time.Second
 	}
 
-	address := target
-	if !strings.Contains(address, ":") {
-		address = net.JoinHostPort(address, "53")
+	status := models.ConnectivityStatus{
+		Probe:  probe.ID,
+		Target: probe.Target,
 	}
 
-	started := time.Now()
-	conn, err := net.DialTimeout("tcp", address, timeout)
+	switch probe.Type {
+	case models.TargetTypeTCP:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		latency, err := dialTCP(ctx, m.clock, probe.Target)
+		cancel()
+		status.LatencyMs = int64(latency / time.Millisecond)
+		if err != nil {
+			status.Error = err.Error()
+		}
+	case models.TargetTypeHTTP:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		latency, err := m.fetch(ctx, probe.Target)
+		cancel()
+		status.LatencyMs = int64(latency / time.Millisecond)
+		if err != nil {
+			status.Error = err.Error()
+		}
+	default:
+		m.resolve(probe, timeout, &status)
+	}
+	status.OK = status.Error == ""
+	status.CheckedAt = m.clock.Now().UTC()
+	return status
+}
 
-	status := models.ConnectivityStatus{
-		Target:    target,
-		CheckedAt: time.Now().UTC(),
+// resolve sends every configured query to the probe's resolver. Every query must resolve; the
+// slowest one determines the reported latency.
+func (m *ConnectivityMonitor) resolve(probe models.ConnectivityProbe, timeout time.Duration, status *models.ConnectivityStatus) {
+	for _, query := range probe.Queries {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		result, err := resolveQuery(ctx, m.clock, probe.Target, query)
+		cancel()
+
+		status.Query = strings.TrimSpace(query.Name + " " + strings.ToUpper(query.Type))
+		status.Rcode = ""
+		if result.Responded {
+			status.Rcode = rcodeName(result.Rcode)
+		}
+		status.Answers = result.Answers
+		if latency := int64(result.Latency / time.Millisecond); latency > status.LatencyMs {
+			status.LatencyMs = latency
+		}
+		if err != nil {
+			status.Error = err.Error()
+			return
+		}
 	}
+}
 
+// fetch requests url and treats any response below 500 as reachable.
+func (m *ConnectivityMonitor) fetch(ctx context.Context, url string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	started := m.clock.Now()
+	resp, err := m.client.Do(req)
+	latency := m.clock.Now().Sub(started)
 	if err != nil {
-		status.Error = err.Error()
-	} else {
-		status.OK = true
-		status.LatencyMs = int64(time.Since(started) / time.Millisecond)
-		_ = conn.Close()
+		return latency, err
 	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return latency, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	return latency, nil
+}
 
+func (m *ConnectivityMonitor) record(status models.ConnectivityStatus) {
 	var historySnapshot []models.ConnectivityStatus
 
 	m.mu.Lock()
-	m.latest = &status
-	m.history = append(m.history, status)
+	m.latest[status.Probe] = status
+	// Probes finish independently, so keep the shared history ordered by check time.
+	idx := sort.Search(len(m.history), func(i int) bool {
+		return m.history[i].CheckedAt.After(status.CheckedAt)
+	})
+	m.history = append(m.history, models.ConnectivityStatus{})
+	copy(m.history[idx+1:], m.history[idx:])
+	m.history[idx] = status
 	if len(m.history) > m.maxHistory {
 		m.history = m.history[len(m.history)-m.maxHistory:]
 	}
@@ -224,10 +310,10 @@ func (m *ConnectivityMonitor) seedFromStore() {
 
 	m.mu.Lock()
 	m.history = append(m.history[:0], history...)
-	if len(m.history) > 0 {
-		latest := m.history[len(m.history)-1]
-		m.latest = new(models.ConnectivityStatus)
-		*m.latest = latest
+	for i := range m.history {
+		// Samples recorded before probes were named belong to the monitor_dns probe.
+		m.history[i].Probe = m.history[i].ProbeID()
+		m.latest[m.history[i].Probe] = m.history[i]
 	}
 	m.mu.Unlock()
 }
@@ -0,0 +1,187 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// maxContainerResponseBytes bounds how much of an inspect response is read.
+const maxContainerResponseBytes = 4 << 20
+
+// containerInspect holds the fields read from GET /containers/{name}/json, which Docker and
+// Podman's compatible API both serve.
+type containerInspect struct {
+	ID     string `json:"Id"`
+	Config struct {
+		Image string `json:"Image"`
+	} `json:"Config"`
+	State struct {
+		Status     string `json:"Status"`
+		OOMKilled  bool   `json:"OOMKilled"`
+		ExitCode   int    `json:"ExitCode"`
+		Error      string `json:"Error"`
+		StartedAt  string `json:"StartedAt"`
+		FinishedAt string `json:"FinishedAt"`
+		Health     *struct {
+			Status        string `json:"Status"`
+			FailingStreak int    `json:"FailingStreak"`
+			Log           []struct {
+				ExitCode int    `json:"ExitCode"`
+				Output   string `json:"Output"`
+			} `json:"Log"`
+		} `json:"Health"`
+	} `json:"State"`
+	RestartCount int `json:"RestartCount"`
+}
+
+func (m *Monitor) checkContainer(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	inspect, found, err := inspectContainer(ctx, target.Container.Socket, target.Container.Name)
+	if err != nil {
+		return failResult(res, "unknown", err.Error())
+	}
+	if !found {
+		return failResult(res, "absent", fmt.Sprintf("container %s does not exist", target.Container.Name))
+	}
+
+	info := containerInfo(inspect)
+	res.Container = &info
+	res.Output = lastHealthOutput(inspect)
+	restarted := m.restartsSince(target.ID, info.RestartCount)
+
+	switch info.Status {
+	case "running":
+		switch info.Health {
+		case "unhealthy":
+			msg := fmt.Sprintf("health check failing (%d in a row)", inspect.State.Health.FailingStreak)
+			if res.Output != "" {
+				msg += ": " + res.Output
+			}
+			return failResult(res, "unhealthy", msg)
+		case "starting":
+			res.OK = true
+			res.State = "starting"
+			return res
+		}
+		res.OK = true
+		res.State = "running"
+		if restarted > 0 {
+			// Docker restarts crashed containers quickly enough to look healthy at the next check.
+			msg := fmt.Sprintf("restarted %d time(s) since last check", restarted)
+			return failResult(res, "restarted", msg)
+		}
+		return res
+	case "paused":
+		return failResult(res, "paused", "container is paused")
+	case "restarting":
+		return failResult(res, "restarting", fmt.Sprintf("restarting (last exit code %d)", info.ExitCode))
+	}
+
+	msg := fmt.Sprintf("%s (exit code %d)", info.Status, info.ExitCode)
+	switch {
+	case inspect.State.OOMKilled:
+		msg = fmt.Sprintf("%s: killed after running out of memory", info.Status)
+	case inspect.State.Error != "":
+		msg = fmt.Sprintf("%s: %s", info.Status, inspect.State.Error)
+	}
+	return failResult(res, fallbackMessage(info.Status, "unknown"), msg)
+}
+
+// inspectContainer queries the Engine API listening on socket. found is false when the API
+// does not know the container.
+func inspectContainer(ctx context.Context, socket, name string) (containerInspect, bool, error) {
+	var dialer net.Dialer
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+			DisableKeepAlives: true,
+		},
+	}
+	// The host is ignored by the dialer; the API is served at the socket's root.
+	endpoint := "http://engine/containers/" + url.PathEscape(name) + "/json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return containerInspect{}, false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return containerInspect{}, false, fmt.Errorf("engine api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxContainerResponseBytes))
+	if err != nil {
+		return containerInspect{}, false, fmt.Errorf("engine api: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return containerInspect{}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return containerInspect{}, false, fmt.Errorf("engine api: %s", fallbackMessage(apiErr.Message, resp.Status))
+	}
+
+	var inspect containerInspect
+	if err := json.Unmarshal(body, &inspect); err != nil {
+		return containerInspect{}, false, fmt.Errorf("engine api: decode inspect response: %w", err)
+	}
+	return inspect, true, nil
+}
+
+func containerInfo(inspect containerInspect) models.ContainerInfo {
+	info := models.ContainerInfo{
+		ID:           inspect.ID,
+		Image:        inspect.Config.Image,
+		Status:       strings.ToLower(inspect.State.Status),
+		RestartCount: inspect.RestartCount,
+		ExitCode:     inspect.State.ExitCode,
+		StartedAt:    parseEngineTime(inspect.State.StartedAt),
+		FinishedAt:   parseEngineTime(inspect.State.FinishedAt),
+	}
+	if len(info.ID) > 12 {
+		info.ID = info.ID[:12]
+	}
+	if health := inspect.State.Health; health != nil {
+		info.Health = strings.ToLower(health.Status)
+	}
+	return info
+}
+
+// lastHealthOutput returns the output of the most recent health check probe.
+func lastHealthOutput(inspect containerInspect) string {
+	health := inspect.State.Health
+	if health == nil || len(health.Log) == 0 {
+		return ""
+	}
+	return truncateOutput(strings.TrimSpace(health.Log[len(health.Log)-1].Output))
+}
+
+// parseEngineTime converts an Engine API timestamp, returning nil for the zero time it reports
+// for containers that never started or finished.
+func parseEngineTime(value string) *time.Time {
+	ts, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil || ts.Year() <= 1 {
+		return nil
+	}
+	utc := ts.UTC()
+	return &utc
+}
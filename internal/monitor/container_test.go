@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"jobmonitor/internal/models"
+)
+
+// fakeEngine serves canned inspect responses on a unix socket and returns its path.
+func fakeEngine(t *testing.T, containers map[string]string) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "engine.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/json")
+		if name == "slow" {
+			<-r.Context().Done()
+			return
+		}
+		body, ok := containers[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"No such container: ` + name + `"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	srv.Listener = listener
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return socket
+}
+
+func TestCheckContainer(t *testing.T) {
+	socket := fakeEngine(t, map[string]string{
+		"web": `{"Id":"0123456789abcdef","Config":{"Image":"nginx:1.27"},"RestartCount":0,
+			"State":{"Status":"running","ExitCode":0,"StartedAt":"2025-10-26T14:00:00.123456789Z","FinishedAt":"0001-01-01T00:00:00Z",
+			"Health":{"Status":"healthy","FailingStreak":0,"Log":[{"ExitCode":0,"Output":"ok\n"}]}}}`,
+		"api": `{"Id":"abc","Config":{"Image":"api:2"},"State":{"Status":"running",
+			"Health":{"Status":"unhealthy","FailingStreak":3,"Log":[{"ExitCode":1,"Output":"connection refused\n"}]}}}`,
+		"worker": `{"Id":"def","Config":{"Image":"worker:1"},"State":{"Status":"exited","ExitCode":137,"OOMKilled":true}}`,
+	})
+
+	tests := []struct {
+		name      string
+		container string
+		timeout   int
+		ok        bool
+		state     string
+		error     string
+	}{
+		{name: "running", container: "web", ok: true, state: "running"},
+		{name: "unhealthy", container: "api", state: "unhealthy", error: "health check failing (3 in a row): connection refused"},
+		{name: "exited", container: "worker", state: "exited", error: "exited: killed after running out of memory"},
+		{name: "missing", container: "gone", state: "absent", error: "container gone does not exist"},
+		{name: "timeout", container: "slow", timeout: 1, state: "unknown", error: "context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := models.Target{
+				ID:             tt.container,
+				Name:           tt.container,
+				Type:           models.TargetTypeContainer,
+				TimeoutSeconds: tt.timeout,
+				Container:      &models.ContainerOptions{Name: tt.container, Socket: socket},
+			}
+			m, _ := newTestMonitor(t, []models.Target{target}, &fakeRunner{})
+
+			res := m.runCheck(context.Background(), target)
+			if res.OK != tt.ok || res.State != tt.state {
+				t.Fatalf("check = %+v, want ok=%v state %q", res, tt.ok, tt.state)
+			}
+			if tt.error == "" {
+				if res.Error != nil {
+					t.Errorf("error = %q, want none", *res.Error)
+				}
+			} else if res.Error == nil || !strings.Contains(*res.Error, tt.error) {
+				t.Errorf("error = %v, want it to contain %q", res.Error, tt.error)
+			}
+		})
+	}
+}
+
+func TestCheckContainerInfo(t *testing.T) {
+	socket := fakeEngine(t, map[string]string{
+		"web": `{"Id":"0123456789abcdef","Config":{"Image":"nginx:1.27"},"RestartCount":2,
+			"State":{"Status":"running","StartedAt":"2025-10-26T14:00:00Z","FinishedAt":"0001-01-01T00:00:00Z"}}`,
+	})
+	inspect, found, err := inspectContainer(context.Background(), socket, "web")
+	if err != nil || !found {
+		t.Fatalf("inspectContainer = found %v, err %v", found, err)
+	}
+	info := containerInfo(inspect)
+	if info.ID != "0123456789ab" || info.Image != "nginx:1.27" || info.RestartCount != 2 || info.StartedAt == nil || info.FinishedAt != nil {
+		t.Errorf("containerInfo = %+v", info)
+	}
+}
@@ -0,0 +1,264 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"jobmonitor/internal/models"
+)
+
+const (
+	systemdDest      = "org.freedesktop.systemd1"
+	systemdPath      = dbus.ObjectPath("/org/freedesktop/systemd1")
+	managerInterface = "org.freedesktop.systemd1.Manager"
+	unitInterface    = "org.freedesktop.systemd1.Unit"
+	serviceInterface = "org.freedesktop.systemd1.Service"
+	timerInterface   = "org.freedesktop.systemd1.Timer"
+	propsInterface   = "org.freedesktop.DBus.Properties"
+)
+
+// unitSuffixes lists unit types recognised when normalising a configured service name.
+var unitSuffixes = []string{
+	".service", ".socket", ".timer", ".target", ".mount", ".automount",
+	".path", ".scope", ".slice", ".device", ".swap",
+}
+
+// UnitBackend reads systemd unit state without forking systemctl.
+type UnitBackend interface {
+	// UnitStatus returns the current properties of a unit.
+	UnitStatus(ctx context.Context, unit string) (models.UnitStatus, error)
+	// Watch invokes fn whenever the active state of one of the units changes. It blocks until ctx is done.
+	Watch(ctx context.Context, units []string, fn func(unit string, status models.UnitStatus)) error
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// DBusBackend talks to systemd over the system bus.
+type DBusBackend struct {
+	conn *dbus.Conn
+
+	mu    sync.Mutex
+	paths map[string]dbus.ObjectPath
+}
+
+// NewDBusBackend connects to the system bus.
+func NewDBusBackend() (*DBusBackend, error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("connect system bus: %w", err)
+	}
+	return &DBusBackend{
+		conn:  conn,
+		paths: make(map[string]dbus.ObjectPath),
+	}, nil
+}
+
+// Close disconnects from the system bus.
+func (b *DBusBackend) Close() error {
+	return b.conn.Close()
+}
+
+// UnitStatus reads unit properties plus service or timer properties for the given unit.
+func (b *DBusBackend) UnitStatus(ctx context.Context, unit string) (models.UnitStatus, error) {
+	name := normalizeUnitName(unit)
+	path, err := b.unitPath(ctx, name)
+	if err != nil {
+		return models.UnitStatus{}, err
+	}
+
+	obj := b.conn.Object(systemdDest, path)
+	var unitProps map[string]dbus.Variant
+	if err := obj.CallWithContext(ctx, propsInterface+".GetAll", 0, unitInterface).Store(&unitProps); err != nil {
+		return models.UnitStatus{}, fmt.Errorf("read unit %s: %w", name, err)
+	}
+	var serviceProps, timerProps map[string]dbus.Variant
+	switch {
+	case strings.HasSuffix(name, ".service"):
+		if err := obj.CallWithContext(ctx, propsInterface+".GetAll", 0, serviceInterface).Store(&serviceProps); err != nil {
+			return models.UnitStatus{}, fmt.Errorf("read service %s: %w", name, err)
+		}
+	case strings.HasSuffix(name, ".timer"):
+		if err := obj.CallWithContext(ctx, propsInterface+".GetAll", 0, timerInterface).Store(&timerProps); err != nil {
+			return models.UnitStatus{}, fmt.Errorf("read timer %s: %w", name, err)
+		}
+	}
+	status := unitStatusFromProps(unitProps, serviceProps)
+	status.LastTrigger = variantTime(timerProps["LastTriggerUSec"])
+	status.NextElapse = variantTime(timerProps["NextElapseUSecRealtime"])
+	return status, nil
+}
+
+// Watch subscribes to PropertiesChanged signals of the given units.
+func (b *DBusBackend) Watch(ctx context.Context, units []string, fn func(unit string, status models.UnitStatus)) error {
+	manager := b.conn.Object(systemdDest, systemdPath)
+	if err := manager.CallWithContext(ctx, managerInterface+".Subscribe", 0).Err; err != nil {
+		return fmt.Errorf("subscribe to systemd: %w", err)
+	}
+	defer manager.Call(managerInterface+".Unsubscribe", 0)
+
+	watched := make(map[dbus.ObjectPath][]string, len(units))
+	for _, unit := range units {
+		path, err := b.unitPath(ctx, normalizeUnitName(unit))
+		if err != nil {
+			return err
+		}
+		if _, ok := watched[path]; !ok {
+			match := []dbus.MatchOption{
+				dbus.WithMatchObjectPath(path),
+				dbus.WithMatchInterface(propsInterface),
+				dbus.WithMatchMember("PropertiesChanged"),
+			}
+			if err := b.conn.AddMatchSignalContext(ctx, match...); err != nil {
+				return fmt.Errorf("watch %s: %w", unit, err)
+			}
+			defer b.conn.RemoveMatchSignal(match...)
+		}
+		watched[path] = append(watched[path], unit)
+	}
+
+	signals := make(chan *dbus.Signal, 64)
+	b.conn.Signal(signals)
+	defer b.conn.RemoveSignal(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig, ok := <-signals:
+			if !ok {
+				return errors.New("system bus connection closed")
+			}
+			names, ok := watched[sig.Path]
+			if !ok || !activeStateChanged(sig) {
+				continue
+			}
+			readCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			status, err := b.UnitStatus(readCtx, names[0])
+			cancel()
+			if err != nil {
+				continue
+			}
+			for _, name := range names {
+				fn(name, status)
+			}
+		}
+	}
+}
+
+func (b *DBusBackend) unitPath(ctx context.Context, name string) (dbus.ObjectPath, error) {
+	b.mu.Lock()
+	path, ok := b.paths[name]
+	b.mu.Unlock()
+	if ok {
+		return path, nil
+	}
+
+	// LoadUnit also resolves units that are not currently loaded, unlike GetUnit.
+	manager := b.conn.Object(systemdDest, systemdPath)
+	if err := manager.CallWithContext(ctx, managerInterface+".LoadUnit", 0, name).Store(&path); err != nil {
+		return "", fmt.Errorf("load unit %s: %w", name, err)
+	}
+
+	b.mu.Lock()
+	b.paths[name] = path
+	b.mu.Unlock()
+	return path, nil
+}
+
+// activeStateChanged reports whether a PropertiesChanged signal touches the unit's active state.
+func activeStateChanged(sig *dbus.Signal) bool {
+	if sig.Name != propsInterface+".PropertiesChanged" || len(sig.Body) < 2 {
+		return false
+	}
+	iface, _ := sig.Body[0].(string)
+	if iface != unitInterface {
+		return false
+	}
+	changed, _ := sig.Body[1].(map[string]dbus.Variant)
+	_, active := changed["ActiveState"]
+	_, sub := changed["SubState"]
+	return active || sub
+}
+
+func unitStatusFromProps(unitProps, serviceProps map[string]dbus.Variant) models.UnitStatus {
+	var unit models.UnitStatus
+	unit.ActiveState = variantString(unitProps["ActiveState"])
+	unit.SubState = variantString(unitProps["SubState"])
+	unit.ActiveSince = variantTime(unitProps["ActiveEnterTimestamp"])
+	if serviceProps != nil {
+		unit.Result = variantString(serviceProps["Result"])
+		unit.NRestarts = int(variantUint(serviceProps["NRestarts"]))
+		unit.MainPID = int(variantUint(serviceProps["ExecMainPID"]))
+		unit.MainStatus = int(variantInt(serviceProps["ExecMainStatus"]))
+		if mem := variantUint(serviceProps["MemoryCurrent"]); mem != ^uint64(0) {
+			unit.MemoryBytes = mem
+		}
+		if cpu := variantUint(serviceProps["CPUUsageNSec"]); cpu != ^uint64(0) {
+			unit.CPUUsageNSec = cpu
+		}
+		unit.LastRunStart = variantTime(serviceProps["ExecMainStartTimestamp"])
+		unit.LastRunEnd = variantTime(serviceProps["ExecMainExitTimestamp"])
+	}
+	return unit
+}
+
+func normalizeUnitName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, suffix := range unitSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return name
+		}
+	}
+	return name + ".service"
+}
+
+func variantString(v dbus.Variant) string {
+	s, _ := v.Value().(string)
+	return s
+}
+
+func variantUint(v dbus.Variant) uint64 {
+	switch n := v.Value().(type) {
+	case uint64:
+		return n
+	case uint32:
+		return uint64(n)
+	case int64:
+		if n > 0 {
+			return uint64(n)
+		}
+	case int32:
+		if n > 0 {
+			return uint64(n)
+		}
+	}
+	return 0
+}
+
+// variantTime converts a systemd microsecond timestamp, returning nil when it is unset.
+func variantTime(v dbus.Variant) *time.Time {
+	usec := variantUint(v)
+	if usec == 0 || usec == ^uint64(0) {
+		return nil
+	}
+	ts := time.UnixMicro(int64(usec)).UTC()
+	return &ts
+}
+
+func variantInt(v dbus.Variant) int64 {
+	switch n := v.Value().(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case uint32:
+		return int64(n)
+	}
+	return 0
+}
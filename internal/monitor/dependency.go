@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"fmt"
+
+	"jobmonitor/internal/models"
+)
+
+// dependencyState is the last known health of a target as seen by its dependents.
+type dependencyState struct {
+	ok        bool
+	blockedBy string
+}
+
+// SetConnectivity lets targets depend on connectivity probes through depends_on. Must be called
+// before Start.
+func (m *Monitor) SetConnectivity(source ConnectivitySource) {
+	m.connectivity = source
+}
+
+// applyDependencies marks failing results whose dependencies are down as "blocked" and records
+// the root cause in BlockedBy. Dependencies checked in the same round use their new results;
+// others use their last known state or the latest connectivity sample.
+func (m *Monitor) applyDependencies(results []models.CheckResult) {
+	probes := make(map[string]models.ConnectivityStatus)
+	if m.connectivity != nil {
+		for _, sample := range m.connectivity.Latest() {
+			probes[sample.ProbeID()] = sample
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current := make(map[string]*models.CheckResult, len(results))
+	for i := range results {
+		current[results[i].ID] = &results[i]
+	}
+	resolved := make(map[string]bool, len(results))
+
+	var block func(res *models.CheckResult)
+	// down reports whether the dependency id is down and which target or probe caused it.
+	down := func(id string) (bool, string) {
+		if res, ok := current[id]; ok {
+			block(res)
+			if res.OK {
+				return false, ""
+			}
+			return true, fallbackMessage(res.BlockedBy, id)
+		}
+		if _, ok := m.targetIndex[id]; ok {
+			state, seen := m.dependencies[id]
+			if !seen || state.ok {
+				return false, ""
+			}
+			return true, fallbackMessage(state.blockedBy, id)
+		}
+		sample, ok := probes[id]
+		return ok && !sample.OK, id
+	}
+	block = func(res *models.CheckResult) {
+		if resolved[res.ID] {
+			return
+		}
+		resolved[res.ID] = true
+		target, ok := m.targetIndex[res.ID]
+		if res.OK || !ok {
+			return
+		}
+		for _, dep := range target.DependsOn {
+			isDown, root := down(dep)
+			if !isDown {
+				continue
+			}
+			msg := fmt.Sprintf("dependency %s is down", dep)
+			if root != dep {
+				msg += fmt.Sprintf(" (root cause: %s)", root)
+			}
+			if cause := valueOrEmpty(res.Error); cause != "" {
+				msg += ": " + cause
+			}
+			res.State = "blocked"
+			res.BlockedBy = root
+			res.Error = &msg
+			return
+		}
+	}
+
+	for i := range results {
+		block(&results[i])
+	}
+	for _, res := range results {
+		m.dependencies[res.ID] = dependencyState{ok: res.OK, blockedBy: res.BlockedBy}
+	}
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// fakeConnectivity serves fixed probe samples.
+type fakeConnectivity struct {
+	latest []models.ConnectivityStatus
+}
+
+func (c fakeConnectivity) Probes() []models.ConnectivityProbe                 { return nil }
+func (c fakeConnectivity) Latest() []models.ConnectivityStatus                { return c.latest }
+func (c fakeConnectivity) History() []models.ConnectivityStatus               { return nil }
+func (c fakeConnectivity) HistorySince(time.Time) []models.ConnectivityStatus { return nil }
+
+func TestRunOnceBlocksDependents(t *testing.T) {
+	targets := []models.Target{
+		{ID: "api", Name: "API", Type: models.TargetTypeSystemd, Service: "api.service", DependsOn: []string{"web"}},
+		{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service", DependsOn: []string{"db"}},
+		{ID: "db", Name: "DB", Type: models.TargetTypeSystemd, Service: "db.service"},
+		{ID: "sync", Name: "Sync", Type: models.TargetTypeSystemd, Service: "sync.service", DependsOn: []string{"gateway"}},
+		{ID: "cache", Name: "Cache", Type: models.TargetTypeSystemd, Service: "cache.service", DependsOn: []string{"db"}},
+	}
+	runner := &fakeRunner{script: func(_ context.Context, name string, args []string) ([]byte, []byte, error) {
+		if systemctlUnit(name, args) == "cache.service" {
+			return unitOutput("active", "success"), nil, nil
+		}
+		return unitOutput("failed", "exit-code"), nil, nil
+	}}
+	m, _ := newTestMonitor(t, targets, runner)
+	m.SetConnectivity(fakeConnectivity{latest: []models.ConnectivityStatus{
+		{Probe: "gateway", OK: false, Error: "i/o timeout"},
+	}})
+
+	entry, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	want := map[string]struct{ state, blockedBy string }{
+		"api":   {"blocked", "db"},
+		"web":   {"blocked", "db"},
+		"db":    {"failed", ""},
+		"sync":  {"blocked", "gateway"},
+		"cache": {"active", ""},
+	}
+	for _, check := range entry.Checks {
+		w := want[check.ID]
+		if check.State != w.state || check.BlockedBy != w.blockedBy {
+			t.Errorf("%s = state %q blocked by %q, want %q blocked by %q", check.ID, check.State, check.BlockedBy, w.state, w.blockedBy)
+		}
+	}
+	api := entry.Checks[0]
+	if want := "dependency web is down (root cause: db): failed (result: exit-code)"; api.Error == nil || *api.Error != want {
+		t.Errorf("api error = %v, want %q", api.Error, want)
+	}
+
+	// A dependent checked on its own uses the last known state of its dependency.
+	results := []models.CheckResult{m.runCheck(context.Background(), targets[1])}
+	m.applyDependencies(results)
+	if results[0].State != "blocked" || results[0].BlockedBy != "db" {
+		t.Errorf("separate web check = %+v, want blocked by db", results[0])
+	}
+}
@@ -0,0 +1,269 @@
+package monitor
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"jobmonitor/internal/models"
+)
+
+// dnsRecordTypes maps configurable record type names onto wire types.
+var dnsRecordTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"MX":    dnsmessage.TypeMX,
+	"TXT":   dnsmessage.TypeTXT,
+	"CNAME": dnsmessage.TypeCNAME,
+	"NS":    dnsmessage.TypeNS,
+}
+
+// dnsResult describes the response to a single query.
+type dnsResult struct {
+	Responded bool
+	Rcode     dnsmessage.RCode
+	Answers   int
+	Values    []string
+	Latency   time.Duration
+}
+
+// parseDNSRecordType validates a record type name; empty means A.
+func parseDNSRecordType(name string) (dnsmessage.Type, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" {
+		return dnsmessage.TypeA, nil
+	}
+	qtype, ok := dnsRecordTypes[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported dns record type %q", name)
+	}
+	return qtype, nil
+}
+
+// resolveQuery sends query to server and validates the response against the expected answers.
+func resolveQuery(ctx context.Context, clock Clock, server string, query models.DNSQuery) (dnsResult, error) {
+	qtype, err := parseDNSRecordType(query.Type)
+	if err != nil {
+		return dnsResult{}, err
+	}
+	result, err := exchangeDNS(ctx, clock, dnsServerAddress(server), query.Name, qtype)
+	if err != nil {
+		return result, err
+	}
+	if result.Rcode != dnsmessage.RCodeSuccess {
+		return result, fmt.Errorf("%s %s: %s", query.Name, dnsTypeName(qtype), rcodeName(result.Rcode))
+	}
+	if len(result.Values) == 0 {
+		return result, fmt.Errorf("%s %s: no answers", query.Name, dnsTypeName(qtype))
+	}
+	for _, expected := range query.Expect {
+		if !containsAnswer(result.Values, expected) {
+			return result, fmt.Errorf("%s %s: answer %q missing (got %s)", query.Name, dnsTypeName(qtype), expected, strings.Join(result.Values, ", "))
+		}
+	}
+	return result, nil
+}
+
+// exchangeDNS sends a recursive query over UDP, retrying over TCP when the answer is truncated.
+func exchangeDNS(ctx context.Context, clock Clock, address, name string, qtype dnsmessage.Type) (dnsResult, error) {
+	qname, err := dnsmessage.NewName(dnsFQDN(name))
+	if err != nil {
+		return dnsResult{}, fmt.Errorf("invalid name %q: %w", name, err)
+	}
+	id := uint16(rand.Intn(1 << 16))
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := msg.Pack()
+	if err != nil {
+		return dnsResult{}, fmt.Errorf("pack query: %w", err)
+	}
+
+	started := clock.Now()
+	raw, err := dnsRoundTrip(ctx, "udp", address, packet)
+	if err != nil {
+		return dnsResult{Latency: clock.Now().Sub(started)}, err
+	}
+	var resp dnsmessage.Message
+	if err := resp.Unpack(raw); err != nil {
+		return dnsResult{Latency: clock.Now().Sub(started)}, fmt.Errorf("parse response: %w", err)
+	}
+	if resp.Truncated {
+		raw, err = dnsRoundTrip(ctx, "tcp", address, packet)
+		if err != nil {
+			return dnsResult{Latency: clock.Now().Sub(started)}, err
+		}
+		if err := resp.Unpack(raw); err != nil {
+			return dnsResult{Latency: clock.Now().Sub(started)}, fmt.Errorf("parse response: %w", err)
+		}
+	}
+	if resp.ID != id {
+		return dnsResult{Latency: clock.Now().Sub(started)}, errors.New("response id mismatch")
+	}
+
+	result := dnsResult{
+		Responded: true,
+		Rcode:     resp.RCode,
+		Answers:   len(resp.Answers),
+		Latency:   clock.Now().Sub(started),
+	}
+	for _, answer := range resp.Answers {
+		if answer.Header.Type != qtype {
+			continue
+		}
+		if value := dnsValue(answer.Body); value != "" {
+			result.Values = append(result.Values, value)
+		}
+	}
+	return result, nil
+}
+
+func dnsRoundTrip(ctx context.Context, network, address string, packet []byte) ([]byte, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		framed := make([]byte, 2+len(packet))
+		binary.BigEndian.PutUint16(framed, uint16(len(packet)))
+		copy(framed[2:], packet)
+		if _, err := conn.Write(framed); err != nil {
+			return nil, err
+		}
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	if _, err := conn.Write(packet); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+func (m *Monitor) checkDNS(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	query := models.DNSQuery{}
+	if target.DNS != nil {
+		query = *target.DNS
+	}
+	server := target.Host
+	if target.Port > 0 {
+		server = net.JoinHostPort(target.Host, fmt.Sprint(target.Port))
+	}
+	result, err := resolveQuery(ctx, m.clock, server, query)
+	res.LatencyMs = int64(result.Latency / time.Millisecond)
+	res.Output = strings.Join(result.Values, ", ")
+	res.Metrics = map[string]models.Metric{
+		"answers": {Value: float64(result.Answers)},
+	}
+	if err != nil {
+		return failResult(res, "failed", err.Error())
+	}
+	res.OK = true
+	res.State = "active"
+	return res
+}
+
+func dnsValue(body dnsmessage.ResourceBody) string {
+	switch r := body.(type) {
+	case *dnsmessage.AResource:
+		return net.IP(r.A[:]).String()
+	case *dnsmessage.AAAAResource:
+		return net.IP(r.AAAA[:]).String()
+	case *dnsmessage.MXResource:
+		return fmt.Sprintf("%d %s", r.Pref, strings.TrimSuffix(r.MX.String(), "."))
+	case *dnsmessage.TXTResource:
+		return strings.Join(r.TXT, "")
+	case *dnsmessage.CNAMEResource:
+		return strings.TrimSuffix(r.CNAME.String(), ".")
+	case *dnsmessage.NSResource:
+		return strings.TrimSuffix(r.NS.String(), ".")
+	}
+	return ""
+}
+
+// containsAnswer compares answers case-insensitively, ignoring trailing dots. MX answers also
+// match on the exchange host alone.
+func containsAnswer(values []string, expected string) bool {
+	expected = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(expected)), ".")
+	for _, value := range values {
+		value = strings.ToLower(value)
+		if value == expected {
+			return true
+		}
+		if _, host, ok := strings.Cut(value, " "); ok && host == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func dnsServerAddress(server string) string {
+	server = strings.TrimSpace(server)
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+func dnsFQDN(name string) string {
+	name = strings.TrimSpace(name)
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	return name
+}
+
+func dnsTypeName(qtype dnsmessage.Type) string {
+	return strings.TrimPrefix(qtype.String(), "Type")
+}
+
+func rcodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return "NOERROR"
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	}
+	return fmt.Sprintf("RCODE%d", rcode)
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// defaultTailLines is used when a file target sets a pattern without file.tail_lines.
+const defaultTailLines = 100
+
+// maxTailBytes bounds how much of a file is read from the end when matching patterns.
+const maxTailBytes = 1 << 20
+
+func (m *Monitor) checkFile(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	opts := models.FileOptions{}
+	if target.File != nil {
+		opts = *target.File
+	}
+
+	info, err := os.Stat(target.Path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return failResult(res, "absent", fmt.Sprintf("%s does not exist", target.Path))
+		}
+		return failResult(res, "unknown", err.Error())
+	}
+
+	age := m.clock.Now().Sub(info.ModTime())
+	if age < 0 {
+		age = 0
+	}
+	res.Metrics = map[string]models.Metric{
+		"age":  {Value: age.Seconds(), Unit: "s"},
+		"size": {Value: float64(info.Size()), Unit: "B"},
+	}
+
+	if opts.MaxAgeSeconds > 0 && age > time.Duration(opts.MaxAgeSeconds)*time.Second {
+		msg := fmt.Sprintf("last modified %s ago (max %s)", age.Round(time.Second), time.Duration(opts.MaxAgeSeconds)*time.Second)
+		return failResult(res, "stale", msg)
+	}
+	if opts.MinBytes > 0 && info.Size() < opts.MinBytes {
+		return failResult(res, "size", fmt.Sprintf("size %d bytes is below %d", info.Size(), opts.MinBytes))
+	}
+	if opts.MaxBytes > 0 && info.Size() > opts.MaxBytes {
+		return failResult(res, "size", fmt.Sprintf("size %d bytes exceeds %d", info.Size(), opts.MaxBytes))
+	}
+
+	if opts.Contains != "" || opts.NotContains != "" {
+		if info.IsDir() {
+			return failResult(res, "unknown", fmt.Sprintf("%s is a directory", target.Path))
+		}
+		lines := opts.TailLines
+		if lines <= 0 {
+			lines = defaultTailLines
+		}
+		tail, err := tailLines(target.Path, lines)
+		if err != nil {
+			return failResult(res, "unknown", err.Error())
+		}
+		if opts.NotContains != "" {
+			pattern, err := m.compilePattern(opts.NotContains)
+			if err != nil {
+				return failResult(res, "unknown", fmt.Sprintf("invalid not_contains: %v", err))
+			}
+			if line, ok := lastMatch(tail, pattern); ok {
+				res.Output = line
+				return failResult(res, "matched", fmt.Sprintf("found %q: %s", opts.NotContains, line))
+			}
+		}
+		if opts.Contains != "" {
+			pattern, err := m.compilePattern(opts.Contains)
+			if err != nil {
+				return failResult(res, "unknown", fmt.Sprintf("invalid contains: %v", err))
+			}
+			line, ok := lastMatch(tail, pattern)
+			if !ok {
+				return failResult(res, "unmatched", fmt.Sprintf("no line matching %q in the last %d lines", opts.Contains, lines))
+			}
+			res.Output = line
+		}
+	}
+
+	res.OK = true
+	res.State = "ok"
+	return res
+}
+
+// tailLines returns up to n trailing lines of the file at path, reading at most maxTailBytes.
+func tailLines(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	offset := size - maxTailBytes
+	if offset < 0 {
+		offset = 0
+	}
+	buf := make([]byte, size-offset)
+	if _, err := f.ReadAt(buf, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	buf = bytes.TrimRight(buf, "\r\n")
+	if len(buf) == 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(buf), "\n")
+	if offset > 0 && len(lines) > 1 {
+		// The first line was cut by the read window.
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
+
+// lastMatch returns the newest line matching pattern.
+func lastMatch(lines []string, pattern *regexp.Regexp) (string, bool) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if pattern.MatchString(lines[i]) {
+			return truncateOutput(strings.TrimRight(lines[i], "\r")), true
+		}
+	}
+	return "", false
+}
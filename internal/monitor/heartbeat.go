@@ -0,0 +1,193 @@
+package monitor
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// maxPingOutput caps how much of a ping body is kept as the result output.
+const maxPingOutput = 1024
+
+// pingEventInterval is the minimum time between two event entries written for the same heartbeat
+// target. State changes from pings arriving sooner are picked up by the next ping or regular sample.
+const pingEventInterval = 10 * time.Second
+
+var (
+	// ErrUnknownHeartbeat is returned for pings addressed to a target that is not a heartbeat target.
+	ErrUnknownHeartbeat = errors.New("unknown heartbeat target")
+	// ErrPingUnauthorized is returned for pings that do not carry the target's token.
+	ErrPingUnauthorized = errors.New("invalid heartbeat token")
+)
+
+// Ping kinds sent by jobs to heartbeat targets.
+const (
+	PingStart   = "start"
+	PingSuccess = "success"
+	PingFail    = "fail"
+)
+
+// Ping is a signal sent by a job to its heartbeat target.
+type Ping struct {
+	Kind     string
+	ExitCode *int
+	Output   string
+	Token    string
+}
+
+// heartbeat holds the pings received for a heartbeat target.
+type heartbeat struct {
+	lastStart time.Time
+	lastPing  time.Time
+	failed    bool
+	exitCode  *int
+	output    string
+	// recorded is when a ping last wrote an event entry.
+	recorded time.Time
+}
+
+// RecordPing stores a ping for the heartbeat target id and records the resulting state change.
+// At most one event entry per pingEventInterval is written for a target.
+func (m *Monitor) RecordPing(id string, ping Ping) error {
+	target, ok := m.heartbeatTarget(id)
+	if !ok {
+		return ErrUnknownHeartbeat
+	}
+	if token := target.Heartbeat.Token; token == "" || subtle.ConstantTimeCompare([]byte(ping.Token), []byte(token)) != 1 {
+		return ErrPingUnauthorized
+	}
+
+	now := m.clock.Now().UTC()
+	m.mu.Lock()
+	hb := m.pings[id]
+	if hb == nil {
+		hb = &heartbeat{}
+		m.pings[id] = hb
+	}
+	kind := ping.Kind
+	if kind == PingSuccess && ping.ExitCode != nil && *ping.ExitCode != 0 {
+		kind = PingFail
+	}
+	switch kind {
+	case PingStart:
+		hb.lastStart = now
+	case PingSuccess, PingFail:
+		hb.lastPing = now
+		hb.failed = kind == PingFail
+		hb.exitCode = ping.ExitCode
+		hb.output = truncateOutput(ping.Output)
+	default:
+		m.mu.Unlock()
+		return fmt.Errorf("unknown ping kind %q", ping.Kind)
+	}
+	throttled := !hb.recorded.IsZero() && now.Sub(hb.recorded) < pingEventInterval
+	m.mu.Unlock()
+
+	if throttled {
+		return nil
+	}
+	if m.recordEvent(target, m.runCheck(context.Background(), target)) {
+		m.mu.Lock()
+		hb.recorded = now
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+func (m *Monitor) heartbeatTarget(id string) (models.Target, bool) {
+	for _, t := range m.targets {
+		if t.ID == id && t.Type == models.TargetTypeHeartbeat {
+			return t, true
+		}
+	}
+	return models.Target{}, false
+}
+
+// checkHeartbeat evaluates the pings received for a heartbeat target. A target fails when the
+// job reported a failure, ran longer than max_runtime_seconds, or did not ping within
+// period_seconds plus grace_seconds of its last ping (or of monitor start).
+func (m *Monitor) checkHeartbeat(_ context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+	opts := target.Heartbeat
+	if opts == nil {
+		return failResult(res, "unknown", "heartbeat options missing")
+	}
+
+	m.mu.Lock()
+	var hb heartbeat
+	if stored := m.pings[target.ID]; stored != nil {
+		hb = *stored
+	}
+	m.mu.Unlock()
+
+	info := &models.HeartbeatInfo{ExitCode: hb.exitCode}
+	if !hb.lastStart.IsZero() {
+		start := hb.lastStart
+		info.LastStart = &start
+	}
+	if !hb.lastPing.IsZero() {
+		last := hb.lastPing
+		info.LastPing = &last
+	}
+	res.Heartbeat = info
+	res.Output = hb.output
+
+	now := m.clock.Now()
+	period := time.Duration(opts.PeriodSeconds) * time.Second
+	grace := time.Duration(opts.GraceSeconds) * time.Second
+	maxRuntime := time.Duration(opts.MaxRuntimeSeconds) * time.Second
+	running := hb.lastStart.After(hb.lastPing)
+
+	since := hb.lastPing
+	if running {
+		since = hb.lastStart
+	}
+	if since.IsZero() {
+		since = m.started
+	}
+
+	switch {
+	case running && maxRuntime > 0 && now.Sub(hb.lastStart) > maxRuntime:
+		msg := fmt.Sprintf("running for %s, longer than max runtime %s", now.Sub(hb.lastStart).Round(time.Second), maxRuntime)
+		return failResult(res, "timeout", msg)
+	case now.Sub(since) > period+grace:
+		msg := fmt.Sprintf("no ping for %s, expected every %s", now.Sub(since).Round(time.Second), period)
+		if grace > 0 {
+			msg += fmt.Sprintf(" (+%s grace)", grace)
+		}
+		return failResult(res, "missed", msg)
+	case running:
+		res.OK = true
+		res.State = "running"
+	case hb.failed:
+		msg := "job reported failure"
+		if hb.exitCode != nil {
+			msg = fmt.Sprintf("job exited with status %d", *hb.exitCode)
+		}
+		return failResult(res, "failed", fallbackMessage(hb.output, msg))
+	case hb.lastPing.IsZero():
+		res.OK = true
+		res.State = "pending"
+	default:
+		res.OK = true
+		res.State = "ok"
+	}
+	return res
+}
+
+func truncateOutput(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxPingOutput {
+		output = output[:maxPingOutput]
+	}
+	return output
+}
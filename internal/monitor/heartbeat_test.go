@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+func pingTarget(token string) models.Target {
+	return models.Target{
+		ID:        "backup",
+		Name:      "Backup",
+		Type:      models.TargetTypeHeartbeat,
+		Heartbeat: &models.HeartbeatOptions{PeriodSeconds: 3600, Token: token},
+	}
+}
+
+func TestRecordPingChecksToken(t *testing.T) {
+	m, _ := newTestMonitor(t, []models.Target{pingTarget("s3cret")}, &fakeRunner{})
+
+	for _, token := range []string{"", "wrong", "s3cret-but-longer"} {
+		if err := m.RecordPing("backup", Ping{Kind: PingSuccess, Token: token}); !errors.Is(err, ErrPingUnauthorized) {
+			t.Errorf("token %q: err = %v, want ErrPingUnauthorized", token, err)
+		}
+	}
+	if err := m.RecordPing("backup", Ping{Kind: PingSuccess, Token: "s3cret"}); err != nil {
+		t.Fatalf("valid token: %v", err)
+	}
+	if err := m.RecordPing("missing", Ping{Kind: PingSuccess}); !errors.Is(err, ErrUnknownHeartbeat) {
+		t.Fatalf("unknown target: err = %v, want ErrUnknownHeartbeat", err)
+	}
+
+	// A target without a token (only possible when built outside config) accepts nothing.
+	m, _ = newTestMonitor(t, []models.Target{pingTarget("")}, &fakeRunner{})
+	if err := m.RecordPing("backup", Ping{Kind: PingSuccess}); !errors.Is(err, ErrPingUnauthorized) {
+		t.Fatalf("target without token: err = %v, want ErrPingUnauthorized", err)
+	}
+}
+
+func TestRecordPingThrottlesEvents(t *testing.T) {
+	m, store := newTestMonitor(t, []models.Target{pingTarget("s3cret")}, &fakeRunner{})
+	if _, err := m.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	events := func() []string {
+		var out []string
+		for _, entry := range store.History() {
+			if entry.Event {
+				out = append(out, entry.Checks[0].State)
+			}
+		}
+		return out
+	}
+	ping := func(offset time.Duration, kind string) {
+		t.Helper()
+		m.SetClock(fakeClock{now: testNow.Add(offset)})
+		if err := m.RecordPing("backup", Ping{Kind: kind, Token: "s3cret"}); err != nil {
+			t.Fatalf("RecordPing(%s): %v", kind, err)
+		}
+	}
+
+	ping(0, PingSuccess)
+	ping(2*time.Second, PingFail)
+	ping(4*time.Second, PingSuccess)
+	if got := events(); len(got) != 1 || got[0] != "ok" {
+		t.Fatalf("events within the interval = %v, want [ok]", got)
+	}
+	ping(pingEventInterval+time.Second, PingFail)
+	if got := events(); len(got) != 2 || got[1] != "failed" {
+		t.Fatalf("events after the interval = %v, want [ok failed]", got)
+	}
+}
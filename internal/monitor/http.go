@@ -0,0 +1,151 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// maxHTTPBodyBytes caps how much of a response body is inspected for matches.
+const maxHTTPBodyBytes = 1 << 20
+
+func newHTTPClient() *http.Client {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	// Request deadlines come from the per-check context.
+	return &http.Client{Transport: transport}
+}
+
+func (m *Monitor) checkHTTP(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	opts := models.HTTPOptions{}
+	if target.HTTP != nil {
+		opts = *target.HTTP
+	}
+	method := strings.ToUpper(strings.TrimSpace(opts.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.URL, nil)
+	if err != nil {
+		return failResult(res, "failed", fmt.Sprintf("build request: %v", err))
+	}
+	for key, value := range opts.Headers {
+		if strings.EqualFold(key, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(key, value)
+	}
+
+	started := m.clock.Now()
+	resp, err := m.client.Do(req)
+	if err != nil {
+		res.LatencyMs = int64(m.clock.Now().Sub(started) / time.Millisecond)
+		return failResult(res, "unreachable", err.Error())
+	}
+	defer resp.Body.Close()
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyBytes))
+	res.LatencyMs = int64(m.clock.Now().Sub(started) / time.Millisecond)
+	res.StatusCode = resp.StatusCode
+
+	if !statusExpected(resp.StatusCode, opts.ExpectedStatus) {
+		return failResult(res, "failed", fmt.Sprintf("unexpected status %s", resp.Status))
+	}
+	if opts.BodyContains != "" || opts.BodyRegex != "" {
+		if readErr != nil {
+			return failResult(res, "failed", fmt.Sprintf("read body: %v", readErr))
+		}
+		if opts.BodyContains != "" && !strings.Contains(string(body), opts.BodyContains) {
+			return failResult(res, "failed", fmt.Sprintf("body does not contain %q", opts.BodyContains))
+		}
+		if opts.BodyRegex != "" {
+			re, err := m.compilePattern(opts.BodyRegex)
+			if err != nil {
+				return failResult(res, "failed", fmt.Sprintf("invalid body_regex: %v", err))
+			}
+			if !re.Match(body) {
+				return failResult(res, "failed", fmt.Sprintf("body does not match %q", opts.BodyRegex))
+			}
+		}
+	}
+
+	res.OK = true
+	res.State = "active"
+	if target.TLS != nil && resp.TLS != nil {
+		// The client already verified the chain; only expiry remains to be judged.
+		res = evaluateCertificate(res, resp.TLS.PeerCertificates, nil, *target.TLS, m.clock.Now())
+		if res.OK {
+			res.State = "active"
+		}
+	}
+	return res
+}
+
+// statusExpected reports whether code is acceptable. Without an explicit list any 2xx/3xx passes.
+func statusExpected(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 400
+	}
+	for _, candidate := range expected {
+		if candidate == code {
+			return true
+		}
+	}
+	return false
+}
+
+func failResult(res models.CheckResult, state, msg string) models.CheckResult {
+	res.OK = false
+	res.State = state
+	res.Error = &msg
+	return res
+}
+
+// warnResult marks res as a warning: the check still counts as up, but msg explains what is
+// close to its limit.
+func warnResult(res models.CheckResult, msg string) models.CheckResult {
+	res.OK = true
+	res.State = "warning"
+	res.Error = &msg
+	return res
+}
+
+// compilePattern returns the compiled form of expr, compiling it on first use.
+func (m *Monitor) compilePattern(expr string) (*regexp.Regexp, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if re, ok := m.patterns[expr]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	m.patterns[expr] = re
+	return re, nil
+}
@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"jobmonitor/internal/models"
+)
+
+func TestCheckHTTPBodyRegex(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"status":"ok","version":"1.4.2"}`)
+	}))
+	defer srv.Close()
+
+	m, _ := newTestMonitor(t, nil, &fakeRunner{})
+	tests := []struct {
+		pattern string
+		wantOK  bool
+	}{
+		{`"version":"1\.\d+\.\d+"`, true},
+		{`"version":"2\.`, false},
+		{`"version":"1\.\d+\.\d+"`, true},
+	}
+	for _, tt := range tests {
+		target := models.Target{ID: "api", Type: models.TargetTypeHTTP, URL: srv.URL, HTTP: &models.HTTPOptions{BodyRegex: tt.pattern}}
+		if res := m.checkHTTP(context.Background(), target); res.OK != tt.wantOK {
+			t.Errorf("body_regex %q: ok = %v (error %v), want %v", tt.pattern, res.OK, valueOrEmpty(res.Error), tt.wantOK)
+		}
+	}
+	if got := len(m.patterns); got != 2 {
+		t.Errorf("compiled %d patterns, want each distinct pattern compiled once (2)", got)
+	}
+}
@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// timerGrace is how long after its due time a timer may take to trigger before the run counts as missed.
+const timerGrace = 2 * time.Minute
+
+// timerWatch remembers when a timer was due next and whether it missed a due time.
+type timerWatch struct {
+	due    time.Time
+	missed time.Time
+}
+
+// checkJob judges a oneshot or timer-driven unit by its last run instead of its active state,
+// which is "inactive" between runs. Each finished run is recorded as an event entry.
+func (m *Monitor) checkJob(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	unit, err := m.unitStatus(ctx, target)
+	if err != nil {
+		return failResult(res, unitErrorState(err), err.Error())
+	}
+	if target.Kind == models.UnitKindTimer {
+		timerTarget := target
+		timerTarget.Service = timerUnit(target)
+		timer, err := m.unitStatus(ctx, timerTarget)
+		if err != nil {
+			return failResult(res, unitErrorState(err), fmt.Sprintf("read timer %s: %v", timerTarget.Service, err))
+		}
+		unit.TimerState = timer.ActiveState
+		unit.LastTrigger = timer.LastTrigger
+		unit.NextElapse = timer.NextElapse
+	}
+	res.Unit = &unit
+	m.recordRun(target, unit)
+
+	running := unit.ActiveState == "activating" || (unit.ActiveState == "active" && unit.SubState == "running")
+	switch {
+	case target.Kind == models.UnitKindTimer && unit.TimerState != "active":
+		return failResult(res, "inactive", fmt.Sprintf("timer %s is %s", timerUnit(target), fallbackMessage(unit.TimerState, "unknown")))
+	case !running && !runSucceeded(unit):
+		return failResult(res, "failed", runFailure(unit))
+	}
+	if target.Kind == models.UnitKindTimer {
+		if missed := m.timerMissed(target.ID, unit, m.clock.Now()); !missed.IsZero() {
+			return failResult(res, "missed", fmt.Sprintf("timer was due at %s but did not run", missed.Local().Format(time.RFC3339)))
+		}
+	}
+	res.OK = true
+	res.State = "ok"
+	if running {
+		res.State = "running"
+	}
+	return res
+}
+
+// timerUnit returns the timer that activates a timer-kind target's service.
+func timerUnit(target models.Target) string {
+	if target.Timer != "" {
+		return normalizeUnitName(target.Timer)
+	}
+	return strings.TrimSuffix(normalizeUnitName(target.Service), ".service") + ".timer"
+}
+
+func runSucceeded(unit models.UnitStatus) bool {
+	return unit.ActiveState != "failed" && (unit.Result == "" || unit.Result == "success") && unit.MainStatus == 0
+}
+
+func runFailure(unit models.UnitStatus) string {
+	return fmt.Sprintf("last run failed (result: %s, exit status %d)", fallbackMessage(unit.Result, "unknown"), unit.MainStatus)
+}
+
+// timerMissed tracks the timer's due times and returns the due time it missed, or zero. A miss
+// is cleared once the timer triggers again.
+func (m *Monitor) timerMissed(id string, unit models.UnitStatus, now time.Time) time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := m.timers[id]
+	if w == nil {
+		w = &timerWatch{}
+		m.timers[id] = w
+	}
+	triggered := func(due time.Time) bool {
+		return unit.LastTrigger != nil && !unit.LastTrigger.Before(due)
+	}
+	if !w.missed.IsZero() && triggered(w.missed) {
+		w.missed = time.Time{}
+	}
+	if !w.due.IsZero() && now.After(w.due.Add(timerGrace)) && !triggered(w.due) {
+		w.missed = w.due
+	}
+	if unit.NextElapse != nil {
+		w.due = *unit.NextElapse
+	}
+	return w.missed
+}
+
+// recordRun stores the unit's last run as an event entry stamped with its exit time, once per run.
+func (m *Monitor) recordRun(target models.Target, unit models.UnitStatus) {
+	if unit.LastRunStart == nil || unit.LastRunEnd == nil || unit.ActiveState == "activating" {
+		return
+	}
+	end := *unit.LastRunEnd
+	m.mu.Lock()
+	if !end.After(m.runs[target.ID]) {
+		m.mu.Unlock()
+		return
+	}
+	m.runs[target.ID] = end
+	m.mu.Unlock()
+
+	res := models.CheckResult{
+		ID:        target.ID,
+		Name:      target.Name,
+		OK:        true,
+		State:     "succeeded",
+		CheckedAt: end,
+		Unit:      &unit,
+		Run: &models.JobRun{
+			Start:      *unit.LastRunStart,
+			End:        end,
+			Result:     unit.Result,
+			ExitStatus: unit.MainStatus,
+		},
+	}
+	if !runSucceeded(unit) {
+		res = failResult(res, "failed", runFailure(unit))
+	}
+	entry := models.StatusEntry{
+		Timestamp: end,
+		Event:     true,
+		Checks:    []models.CheckResult{res},
+	}
+	if err := m.storage.Append(entry); err != nil {
+		log.Printf("record run of %s failed: %v", target.ID, err)
+	}
+}
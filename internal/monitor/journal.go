@@ -0,0 +1,143 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// maxJournalLines bounds how many of the counted journal lines are kept on a result.
+const maxJournalLines = 5
+
+// journalEntry holds the fields read from `journalctl -o json`. MESSAGE is an array of bytes
+// when the message is not valid UTF-8.
+type journalEntry struct {
+	Timestamp string          `json:"__REALTIME_TIMESTAMP"`
+	Priority  string          `json:"PRIORITY"`
+	Message   json.RawMessage `json:"MESSAGE"`
+}
+
+// journalctlCommand builds the command line for journalctl like systemctlCommand does for systemctl.
+func journalctlCommand(target models.Target, args ...string) (string, []string) {
+	if target.UseSudo {
+		return remoteCommand(target, "sudo", append([]string{"journalctl"}, args...))
+	}
+	return remoteCommand(target, "journalctl", args)
+}
+
+// checkJournal counts the target's recent journal lines and folds them into res. A unit that is
+// already failing keeps its state; the lines are attached to explain it.
+func (m *Monitor) checkJournal(ctx context.Context, target models.Target, res models.CheckResult) models.CheckResult {
+	opts := *target.Journal
+	window := time.Duration(opts.WindowSeconds) * time.Second
+	if window <= 0 {
+		window = m.targetInterval(target)
+	}
+
+	info, err := m.readJournal(ctx, target, m.clock.Now().Add(-window))
+	if err != nil {
+		if !res.OK {
+			return res
+		}
+		return failResult(res, "unknown", fmt.Sprintf("read journal: %v", err))
+	}
+	res.Journal = info
+	if !res.OK || info.Count == 0 {
+		return res
+	}
+
+	msg := fmt.Sprintf("%d journal line(s) in the last %s", info.Count, window)
+	if len(info.Lines) > 0 {
+		msg += ": " + info.Lines[len(info.Lines)-1].Message
+	}
+	switch {
+	case exceedsThreshold(info.Count, opts.ErrorAbove):
+		return failResult(res, "errors", msg)
+	case exceedsThreshold(info.Count, opts.WarnAbove):
+		return warnResult(res, msg)
+	}
+	return res
+}
+
+// targetInterval returns how often a target is checked on its regular schedule.
+func (m *Monitor) targetInterval(target models.Target) time.Duration {
+	if target.IntervalSeconds > 0 {
+		return time.Duration(target.IntervalSeconds) * time.Second
+	}
+	return m.interval
+}
+
+func exceedsThreshold(count int, threshold *int) bool {
+	return threshold != nil && count > *threshold
+}
+
+// readJournal returns the lines of the target's unit logged since the given time that are at or
+// above the configured priority or match the configured pattern.
+func (m *Monitor) readJournal(ctx context.Context, target models.Target, since time.Time) (*models.JournalInfo, error) {
+	opts := *target.Journal
+	level, _ := models.ParseJournalPriority(opts.Priority)
+	var pattern *regexp.Regexp
+	if opts.Pattern != "" {
+		var err error
+		if pattern, err = m.compilePattern(opts.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid journal pattern: %w", err)
+		}
+	}
+
+	args := []string{"-u", target.Service, "--since", fmt.Sprintf("@%d", since.Unix()), "--no-pager", "--quiet", "--output=json"}
+	cmdName, cmdArgs := journalctlCommand(target, args...)
+	stdout, stderr, err := m.runner.Run(ctx, cmdName, cmdArgs...)
+	if err != nil {
+		return nil, targetCommandError(ctx, target, "journalctl", stdout, stderr, err)
+	}
+
+	info := &models.JournalInfo{Since: since.UTC()}
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	scanner.Buffer(make([]byte, 0, 64<<10), 4<<20)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		line := parseJournalEntry(entry)
+		if line.Priority > level && (pattern == nil || !pattern.MatchString(line.Message)) {
+			continue
+		}
+		info.Count++
+		info.Lines = append(info.Lines, line)
+		if len(info.Lines) > maxJournalLines {
+			info.Lines = info.Lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func parseJournalEntry(entry journalEntry) models.JournalLine {
+	line := models.JournalLine{Priority: 6}
+	if usec, err := strconv.ParseInt(entry.Timestamp, 10, 64); err == nil {
+		line.Timestamp = time.UnixMicro(usec).UTC()
+	}
+	if priority, err := strconv.Atoi(entry.Priority); err == nil {
+		line.Priority = priority
+	}
+	var text string
+	if err := json.Unmarshal(entry.Message, &text); err != nil {
+		var raw []byte
+		if json.Unmarshal(entry.Message, &raw) == nil {
+			text = strings.ToValidUTF8(string(raw), "�")
+		}
+	}
+	line.Message = truncateOutput(strings.TrimSpace(text))
+	return line
+}
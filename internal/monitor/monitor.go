@@ -2,21 +2,45 @@ package monitor
 
 import (
 	"context"
-	"errors"
 	"log"
-	"os/exec"
-	"strings"
+	"net/http"
+	"regexp"
+	"sync"
 	"time"
 
 	"jobmonitor/internal/models"
 	"jobmonitor/internal/storage"
 )
 
+// DefaultMaxParallel bounds how many targets are checked at the same time.
+const DefaultMaxParallel = 8
+
 // Monitor periodically checks targets and persists their status.
 type Monitor struct {
-	interval time.Duration
-	targets  []models.Target
-	storage  *storage.StatusStorage
+	interval     time.Duration
+	targets      []models.Target
+	targetIndex  map[string]models.Target
+	storage      *storage.StatusStorage
+	client       *http.Client
+	units        UnitBackend
+	connectivity ConnectivitySource
+	runner       CommandRunner
+	clock        Clock
+	maxParallel  int
+	started      time.Time
+
+	mu           sync.Mutex
+	restarts     map[string]int
+	states       map[string]string
+	streaks      map[streakKey]*streak
+	pings        map[string]*heartbeat
+	runs         map[string]time.Time
+	timers       map[string]*timerWatch
+	cpu          map[string]cpuSample
+	dependencies map[string]dependencyState
+	remediations map[string]*remediationWatch
+	checkers     map[string]Checker
+	patterns     map[string]*regexp.Regexp
 
 	stopCh chan struct{}
 	doneCh chan struct{}
@@ -28,12 +52,89 @@ func New(interval time.Duration, targets []models.Target, storage *storage.Statu
 		interval = time.Minute
 	}
 
-	return &Monitor{
-		interval: interval,
-		targets:  targets,
-		storage:  storage,
-		stopCh:   make(chan struct{}),
-		doneCh:   make(chan struct{}),
+	m := &Monitor{
+		interval:     interval,
+		targets:      targets,
+		storage:      storage,
+		client:       newHTTPClient(),
+		runner:       execRunner{},
+		clock:        systemClock{},
+		maxParallel:  DefaultMaxParallel,
+		started:      time.Now(),
+		restarts:     make(map[string]int),
+		states:       make(map[string]string),
+		streaks:      make(map[streakKey]*streak),
+		pings:        make(map[string]*heartbeat),
+		runs:         make(map[string]time.Time),
+		timers:       make(map[string]*timerWatch),
+		cpu:          make(map[string]cpuSample),
+		dependencies: make(map[string]dependencyState),
+		remediations: make(map[string]*remediationWatch),
+		patterns:     make(map[string]*regexp.Regexp),
+		targetIndex:  make(map[string]models.Target, len(targets)),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}
+	for _, t := range targets {
+		m.targetIndex[t.ID] = t
+	}
+	m.checkers = m.builtinCheckers()
+	m.seedFromStorage()
+	return m
+}
+
+// seedFromStorage restores the last pings of heartbeat targets and the last recorded job runs
+// from the stored history.
+func (m *Monitor) seedFromStorage() {
+	latest, ok := m.storage.Latest()
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, check := range latest.Checks {
+		if check.Unit != nil && check.Unit.LastRunEnd != nil {
+			m.runs[check.ID] = *check.Unit.LastRunEnd
+		}
+		info := check.Heartbeat
+		if info == nil {
+			continue
+		}
+		hb := &heartbeat{exitCode: info.ExitCode, output: check.Output}
+		if info.LastStart != nil {
+			hb.lastStart = *info.LastStart
+		}
+		if info.LastPing != nil {
+			hb.lastPing = *info.LastPing
+			hb.failed = check.State == "failed"
+		}
+		m.pings[check.ID] = hb
+	}
+}
+
+// SetUnitBackend makes systemd targets use backend instead of forking systemctl. It also
+// enables recording of unit state transitions between regular samples. Must be called before Start.
+func (m *Monitor) SetUnitBackend(backend UnitBackend) {
+	m.units = backend
+}
+
+// SetCommandRunner replaces how external commands are run. Must be called before Start.
+func (m *Monitor) SetCommandRunner(runner CommandRunner) {
+	m.runner = runner
+}
+
+// SetClock replaces the source of the current time used for result timestamps and deadlines;
+// the monitor's start time is taken from the new clock. The scheduling loop keeps using real
+// timers. Must be called before Start.
+func (m *Monitor) SetClock(clock Clock) {
+	m.clock = clock
+	m.started = clock.Now()
+}
+
+// SetMaxParallel sets how many targets may be checked concurrently. Values below one keep the default.
+func (m *Monitor) SetMaxParallel(n int) {
+	if n > 0 {
+		m.maxParallel = n
 	}
 }
 
@@ -53,24 +154,37 @@ func (m *Monitor) Stop() {
 	<-m.doneCh
 }
 
-// RunOnce executes a single round of checks and returns the entry.
+// RunOnce executes a single round of checks for all targets and returns the entry.
 func (m *Monitor) RunOnce(ctx context.Context) (models.StatusEntry, error) {
+	return m.runTargets(ctx, m.targets)
+}
+
+// runTargets checks the given targets concurrently and stores them as one entry.
+func (m *Monitor) runTargets(ctx context.Context, targets []models.Target) (models.StatusEntry, error) {
 	entry := models.StatusEntry{
-		Timestamp: time.Now().UTC(),
-		Checks:    make([]models.CheckResult, 0, len(m.targets)),
+		Timestamp: m.clock.Now().UTC(),
+		Checks:    make([]models.CheckResult, 0, len(targets)),
 	}
 
-	for _, t := range m.targets {
-		checkCtx := ctx
-		var cancel context.CancelFunc
-		timeout := time.Duration(t.TimeoutSeconds) * time.Second
-		if timeout <= 0 {
-			timeout = 15 * time.Second
-		}
-		checkCtx, cancel = context.WithTimeout(checkCtx, timeout)
-		result := m.checkTarget(checkCtx, t)
-		cancel()
+	results := make([]models.CheckResult, len(targets))
+	sem := make(chan struct{}, m.maxParallel)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, t models.Target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = m.checkWithPolicy(ctx, t)
+		}(i, t)
+	}
+	wg.Wait()
 
+	m.recordRestarts(results)
+	m.applyDependencies(results)
+	m.remediate(ctx, targets, results)
+	for _, result := range results {
+		m.recordState(result.ID, result.State)
 		entry.Checks = append(entry.Checks, result)
 	}
 
@@ -80,61 +194,129 @@ func (m *Monitor) RunOnce(ctx context.Context) (models.StatusEntry, error) {
 	return entry, nil
 }
 
+// runCheck checks a single target, bounded by its timeout.
+func (m *Monitor) runCheck(ctx context.Context, t models.Target) models.CheckResult {
+	checkCtx, cancel := context.WithTimeout(ctx, checkTimeout(t))
+	defer cancel()
+
+	started := m.clock.Now()
+	result := m.checkTarget(checkCtx, t)
+	result.CheckedAt = started.UTC()
+	result.DurationMs = int64(m.clock.Now().Sub(started) / time.Millisecond)
+	return result
+}
+
+// checkTimeout returns how long a single check of t may take.
+func checkTimeout(t models.Target) time.Duration {
+	timeout := time.Duration(t.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = 15 * time.Second
+	}
+	return timeout
+}
+
 func (m *Monitor) run() {
 	defer close(m.doneCh)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	if m.units != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.watchUnits(ctx)
+		}()
+	}
+	for _, t := range m.targets {
+		if t.WatchSeconds <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(t models.Target) {
+			defer wg.Done()
+			m.watchTarget(ctx, t)
+		}(t)
+	}
+
 	if _, err := m.RunOnce(context.Background()); err != nil {
 		log.Printf("initial check failed: %v", err)
 	}
 
-	ticker := time.NewTicker(m.interval)
-	defer ticker.Stop()
+	plan := m.newSchedule(time.Now())
+	timer := time.NewTimer(time.Until(nextRun(plan)))
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			if _, err := m.RunOnce(context.Background()); err != nil {
-				log.Printf("monitor tick failed: %v", err)
+		case <-timer.C:
+			due := dueTargets(plan, time.Now())
+			if len(due) > 0 {
+				if _, err := m.runTargets(context.Background(), due); err != nil {
+					log.Printf("monitor tick failed: %v", err)
+				}
 			}
+			timer.Reset(time.Until(nextRun(plan)))
 		case <-m.stopCh:
 			return
 		}
 	}
 }
 
-func (m *Monitor) checkTarget(ctx context.Context, target models.Target) models.CheckResult {
-	res := models.CheckResult{
-		ID:   target.ID,
-		Name: target.Name,
-		OK:   false,
+// watchTarget polls a target every WatchSeconds and stores only state transitions.
+func (m *Monitor) watchTarget(ctx context.Context, t models.Target) {
+	ticker := time.NewTicker(time.Duration(t.WatchSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			result := m.runCheck(ctx, t)
+			if ctx.Err() != nil {
+				return
+			}
+			m.recordEvent(t, result)
+		case <-ctx.Done():
+			return
+		}
 	}
+}
 
-	args := []string{"is-active", target.Service}
-	cmdName := "systemctl"
-	if target.UseSudo {
-		args = append([]string{"systemctl"}, args...)
-		cmdName = "sudo"
+// recordEvent applies the target's confirmation policy to a raw result observed between regular
+// samples and appends an event entry when the confirmed state differs from the last one seen.
+// Raw outcomes still awaiting confirmation are not transitions and are not recorded. It reports
+// whether an entry was written.
+func (m *Monitor) recordEvent(t models.Target, result models.CheckResult) bool {
+	m.recordRestarts([]models.CheckResult{result})
+	result = m.applyPolicy(t, sourceEvent, result, []models.CheckAttempt{rawAttempt(result)})
+	if pendingConfirmation(result) {
+		return false
 	}
-	cmd := exec.CommandContext(ctx, cmdName, args...)
-	output, err := cmd.CombinedOutput()
-	state := strings.TrimSpace(string(output))
-	if state == "" {
-		state = "unknown"
+	results := []models.CheckResult{result}
+	m.applyDependencies(results)
+	result = results[0]
+	if !m.recordState(result.ID, result.State) {
+		return false
 	}
-	res.State = state
-	res.OK = strings.EqualFold(state, "active")
-	if err != nil {
-		// Preserve useful stdout/stderr text if available.
-		msg := strings.TrimSpace(string(output))
-		var exitErr *exec.ExitError
-		if msg == "" && errors.As(err, &exitErr) {
-			msg = exitErr.Error()
-		}
-		if msg == "" {
-			msg = err.Error()
-		}
-		res.Error = &msg
+	entry := models.StatusEntry{
+		Timestamp: m.clock.Now().UTC(),
+		Event:     true,
+		Checks:    []models.CheckResult{result},
+	}
+	if err := m.storage.Append(entry); err != nil {
+		log.Printf("record transition for %s failed: %v", result.ID, err)
+		return false
 	}
+	return true
+}
+
+// recordState stores the last observed state and reports whether it changed.
+func (m *Monitor) recordState(id, state string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	return res
+	prev, seen := m.states[id]
+	m.states[id] = state
+	return seen && prev != state
 }
@@ -0,0 +1,322 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+	"jobmonitor/internal/storage"
+)
+
+// fakeRunner answers commands from a script and records every call.
+type fakeRunner struct {
+	mu     sync.Mutex
+	calls  [][]string
+	script func(ctx context.Context, name string, args []string) ([]byte, []byte, error)
+}
+
+func (r *fakeRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, append([]string{name}, args...))
+	r.mu.Unlock()
+	return r.script(ctx, name, args)
+}
+
+func (r *fakeRunner) commands() [][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]string(nil), r.calls...)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+// exitStatus is a command error carrying an exit code, like *exec.ExitError.
+type exitStatus int
+
+func (e exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+
+func (e exitStatus) ExitCode() int { return int(e) }
+
+var testNow = time.Date(2025, 10, 26, 15, 0, 0, 0, time.UTC)
+
+func newTestMonitor(t *testing.T, targets []models.Target, runner CommandRunner) (*Monitor, *storage.StatusStorage) {
+	t.Helper()
+	store, err := storage.NewStatusStorage(filepath.Join(t.TempDir(), "status_history.json"))
+	if err != nil {
+		t.Fatalf("NewStatusStorage: %v", err)
+	}
+	m := New(time.Minute, targets, store)
+	m.SetCommandRunner(runner)
+	m.SetClock(fakeClock{now: testNow})
+	return m, store
+}
+
+// unitOutput returns `systemctl show` output for a unit in the given state.
+func unitOutput(active, result string) []byte {
+	return []byte(fmt.Sprintf("ActiveState=%s\nSubState=dead\nResult=%s\nNRestarts=0\nExecMainPID=0\n", active, result))
+}
+
+// systemctlUnit returns the unit a systemctl invocation (with or without sudo) asks about.
+func systemctlUnit(name string, args []string) string {
+	if name == "sudo" {
+		args = args[1:]
+	}
+	if len(args) < 2 || args[0] != "show" {
+		return ""
+	}
+	return args[1]
+}
+
+func TestRunOnceRecordsSystemdResults(t *testing.T) {
+	targets := []models.Target{
+		{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service"},
+		{ID: "worker", Name: "Worker", Type: models.TargetTypeSystemd, Service: "worker.service"},
+	}
+	runner := &fakeRunner{script: func(_ context.Context, name string, args []string) ([]byte, []byte, error) {
+		switch systemctlUnit(name, args) {
+		case "web.service":
+			return unitOutput("active", "success"), nil, nil
+		case "worker.service":
+			return unitOutput("failed", "exit-code"), nil, nil
+		}
+		return nil, []byte("unexpected command"), exitStatus(1)
+	}}
+	m, store := newTestMonitor(t, targets, runner)
+
+	entry, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if !entry.Timestamp.Equal(testNow) {
+		t.Errorf("entry timestamp = %v, want %v", entry.Timestamp, testNow)
+	}
+	if len(entry.Checks) != 2 {
+		t.Fatalf("got %d checks, want 2", len(entry.Checks))
+	}
+
+	web, worker := entry.Checks[0], entry.Checks[1]
+	if web.ID != "web" || !web.OK || web.State != "active" || web.Error != nil {
+		t.Errorf("web = %+v, want OK active result", web)
+	}
+	if !web.CheckedAt.Equal(testNow) {
+		t.Errorf("web checked_at = %v, want %v", web.CheckedAt, testNow)
+	}
+	if worker.ID != "worker" || worker.OK || worker.State != "failed" {
+		t.Errorf("worker = %+v, want failed result", worker)
+	}
+	if worker.Error == nil || *worker.Error != "failed (result: exit-code)" {
+		t.Errorf("worker error = %v, want %q", worker.Error, "failed (result: exit-code)")
+	}
+
+	latest, ok := store.Latest()
+	if !ok || len(latest.Checks) != 2 {
+		t.Fatalf("stored latest = %+v, want both results", latest)
+	}
+	if got := len(runner.commands()); got != 2 {
+		t.Errorf("runner called %d times, want 2", got)
+	}
+}
+
+func TestRunOnceReportsSystemctlFailure(t *testing.T) {
+	targets := []models.Target{{ID: "db", Name: "DB", Type: models.TargetTypeSystemd, Service: "db.service"}}
+	runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+		return nil, []byte("Failed to connect to bus: No such file or directory\n"), exitStatus(1)
+	}}
+	m, _ := newTestMonitor(t, targets, runner)
+
+	entry, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	check := entry.Checks[0]
+	if check.OK || check.State != "unknown" {
+		t.Fatalf("check = %+v, want unknown failure", check)
+	}
+	if want := "Failed to connect to bus: No such file or directory"; check.Error == nil || *check.Error != want {
+		t.Errorf("error = %v, want %q", check.Error, want)
+	}
+}
+
+func TestRunOnceUsesSudo(t *testing.T) {
+	targets := []models.Target{{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service", UseSudo: true}}
+	runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+		return unitOutput("active", "success"), nil, nil
+	}}
+	m, _ := newTestMonitor(t, targets, runner)
+
+	if _, err := m.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	calls := runner.commands()
+	if len(calls) != 1 {
+		t.Fatalf("runner called %d times, want 1", len(calls))
+	}
+	if got := strings.Join(calls[0][:4], " "); got != "sudo systemctl show web.service" {
+		t.Errorf("command = %q, want it to start with %q", strings.Join(calls[0], " "), "sudo systemctl show web.service")
+	}
+}
+
+func TestRunOnceTimesOut(t *testing.T) {
+	targets := []models.Target{{ID: "slow", Name: "Slow", Type: models.TargetTypeSystemd, Service: "slow.service", TimeoutSeconds: 1}}
+	runner := &fakeRunner{script: func(ctx context.Context, _ string, _ []string) ([]byte, []byte, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}}
+	m, _ := newTestMonitor(t, targets, runner)
+
+	started := time.Now()
+	entry, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("RunOnce took %v, want the 1s target timeout to apply", elapsed)
+	}
+	check := entry.Checks[0]
+	if check.OK || check.State != "unknown" {
+		t.Fatalf("check = %+v, want unknown failure", check)
+	}
+	if check.Error == nil || !strings.Contains(*check.Error, "systemctl timed out") {
+		t.Errorf("error = %v, want a systemctl timeout", check.Error)
+	}
+}
+
+func TestCommandTargetExitCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		stdout    string
+		stderr    string
+		err       error
+		wantOK    bool
+		wantState string
+		wantError string
+	}{
+		{name: "ok", stdout: "DISK OK|used=40%;80;90", wantOK: true, wantState: "ok"},
+		{name: "warning", stdout: "DISK WARNING - 85% used", err: exitStatus(1), wantState: "warning", wantError: "DISK WARNING - 85% used"},
+		{name: "critical", stdout: "DISK CRITICAL - 95% used|used=95%;80;90", err: exitStatus(2), wantState: "critical", wantError: "DISK CRITICAL - 95% used"},
+		{name: "stderr fallback", stderr: "check_disk: permission denied", err: exitStatus(3), wantState: "unknown", wantError: "check_disk: permission denied"},
+		{name: "unexpected exit", err: exitStatus(127), wantState: "critical", wantError: "exit status 127"},
+		{name: "start failure", err: fmt.Errorf("exec: \"check_disk\": executable file not found in $PATH"), wantState: "unknown", wantError: "exec: \"check_disk\": executable file not found in $PATH"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := models.Target{ID: "disk", Name: "Disk", Type: models.TargetTypeCommand, Command: "check_disk"}
+			runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+				return []byte(tt.stdout), []byte(tt.stderr), tt.err
+			}}
+			m, _ := newTestMonitor(t, []models.Target{target}, runner)
+
+			res := m.runCheck(context.Background(), target)
+			if res.OK != tt.wantOK || res.State != tt.wantState {
+				t.Errorf("result = ok %v state %q, want ok %v state %q", res.OK, res.State, tt.wantOK, tt.wantState)
+			}
+			gotError := ""
+			if res.Error != nil {
+				gotError = *res.Error
+			}
+			if gotError != tt.wantError {
+				t.Errorf("error = %q, want %q", gotError, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestRecordEventAppliesPolicy(t *testing.T) {
+	target := models.Target{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service", FailAfter: 2, RecoverAfter: 2}
+	active := "active"
+	runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+		return unitOutput(active, "success"), nil, nil
+	}}
+	m, store := newTestMonitor(t, []models.Target{target}, runner)
+	if _, err := m.RunOnce(context.Background()); err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+
+	events := func() []models.CheckResult {
+		var out []models.CheckResult
+		for _, entry := range store.History() {
+			if entry.Event {
+				out = append(out, entry.Checks...)
+			}
+		}
+		return out
+	}
+	watch := func() { m.recordEvent(target, m.runCheck(context.Background(), target)) }
+
+	active = "failed"
+	watch()
+	if got := events(); len(got) != 0 {
+		t.Fatalf("single raw failure recorded events %+v, want none", got)
+	}
+	watch()
+	if got := events(); len(got) != 1 || got[0].OK || got[0].State != "failed" {
+		t.Fatalf("confirmed failure events = %+v, want one failed event", got)
+	}
+	active = "active"
+	watch()
+	if got := events(); len(got) != 1 {
+		t.Fatalf("recovery before confirmation recorded events %+v", got[1:])
+	}
+	watch()
+	if got := events(); len(got) != 2 || !got[1].OK || got[1].State != "active" {
+		t.Fatalf("confirmed recovery events = %+v, want an active event", got)
+	}
+}
+
+func TestPolicyStreaksPerSource(t *testing.T) {
+	target := models.Target{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service", FailAfter: 3}
+	active := "active"
+	runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+		return unitOutput(active, "success"), nil, nil
+	}}
+	m, store := newTestMonitor(t, []models.Target{target}, runner)
+	sample := func() models.CheckResult {
+		t.Helper()
+		entry, err := m.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		return entry.Checks[0]
+	}
+	watch := func() { m.recordEvent(target, m.runCheck(context.Background(), target)) }
+	events := func() int {
+		count := 0
+		for _, entry := range store.History() {
+			if entry.Event {
+				count++
+			}
+		}
+		return count
+	}
+
+	sample()
+	active = "failed"
+	watch()
+	watch()
+	// Two failing polls do not count towards the samples' streak.
+	if res := sample(); res.State != "unconfirmed" || !strings.HasPrefix(valueOrEmpty(res.Error), "failure 1/3") {
+		t.Fatalf("first failing sample = %+v, want unconfirmed failure 1/3", res)
+	}
+	if got := events(); got != 0 {
+		t.Fatalf("%d events after two failing polls, want none", got)
+	}
+	watch()
+	if got := events(); got != 1 {
+		t.Fatalf("%d events after three failing polls, want one", got)
+	}
+	if res := sample(); res.State != "unconfirmed" {
+		t.Fatalf("second failing sample = %+v, want unconfirmed", res)
+	}
+	if res := sample(); res.OK || res.State != "failed" {
+		t.Fatalf("third failing sample = %+v, want failed", res)
+	}
+}
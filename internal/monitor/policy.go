@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// streak tracks consecutive raw outcomes and the confirmed state of a target.
+type streak struct {
+	confirmedOK bool
+	failures    int
+	successes   int
+}
+
+// Sources of raw results. Each source keeps its own streak, so fail_after and recover_after count
+// either regular samples or results seen between them (watch polls, unit events, pings), never a
+// mix of both.
+const (
+	sourceSample = "sample"
+	sourceEvent  = "event"
+)
+
+// streakKey identifies the streak of a target for one source.
+type streakKey struct {
+	id     string
+	source string
+}
+
+// checkWithPolicy runs a check with the target's retry and confirmation settings. The returned
+// OK/State reflect the confirmed state; raw tries are kept in Attempts whenever they differ from it
+// or more than one try was needed.
+func (m *Monitor) checkWithPolicy(ctx context.Context, t models.Target) models.CheckResult {
+	delay := time.Duration(t.RetryDelaySeconds) * time.Second
+
+	var (
+		result   models.CheckResult
+		attempts []models.CheckAttempt
+	)
+	for try := 0; try <= t.Retries; try++ {
+		if try > 0 && delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+			}
+		}
+		result = m.runCheck(ctx, t)
+		attempts = append(attempts, rawAttempt(result))
+		if result.OK || ctx.Err() != nil {
+			break
+		}
+	}
+	return m.applyPolicy(t, sourceSample, result, attempts)
+}
+
+// applyPolicy feeds a raw result into the target's streak for source and rewrites it to the
+// confirmed state. attempts are the raw tries that produced the result.
+func (m *Monitor) applyPolicy(t models.Target, source string, result models.CheckResult, attempts []models.CheckAttempt) models.CheckResult {
+	key := streakKey{id: t.ID, source: source}
+	confirmed := m.confirm(t, key, result.OK)
+	if len(attempts) > 1 || confirmed != result.OK {
+		result.Attempts = attempts
+	}
+	if confirmed == result.OK {
+		return result
+	}
+
+	if confirmed {
+		// Failure not yet confirmed: keep the target up but flag the raw error.
+		result.OK = true
+		result.State = "unconfirmed"
+		msg := fmt.Sprintf("failure %d/%d before confirmation: %s", m.failureCount(key), policyThreshold(t.FailAfter), valueOrEmpty(result.Error))
+		result.Error = &msg
+		return result
+	}
+	msg := fmt.Sprintf("recovering, %d/%d successful checks", m.successCount(key), policyThreshold(t.RecoverAfter))
+	result.OK = false
+	result.State = "recovering"
+	result.Error = &msg
+	return result
+}
+
+// confirm updates a streak with a raw outcome and returns the confirmed state. A new streak
+// starts from the state confirmed by the other source, if any.
+func (m *Monitor) confirm(t models.Target, key streakKey, ok bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st, seen := m.streaks[key]
+	if !seen {
+		st = &streak{confirmedOK: ok}
+		for other, existing := range m.streaks {
+			if other.id == key.id {
+				st.confirmedOK = existing.confirmedOK
+			}
+		}
+		m.streaks[key] = st
+	}
+	if ok {
+		st.successes++
+		st.failures = 0
+		if !st.confirmedOK && st.successes >= policyThreshold(t.RecoverAfter) {
+			st.confirmedOK = true
+		}
+	} else {
+		st.failures++
+		st.successes = 0
+		if st.confirmedOK && st.failures >= policyThreshold(t.FailAfter) {
+			st.confirmedOK = false
+		}
+	}
+	return st.confirmedOK
+}
+
+// pendingConfirmation reports whether a confirmed result only flags a raw outcome that has not
+// flipped the confirmed state yet.
+func pendingConfirmation(result models.CheckResult) bool {
+	return result.State == "unconfirmed" || result.State == "recovering"
+}
+
+func rawAttempt(result models.CheckResult) models.CheckAttempt {
+	return models.CheckAttempt{
+		OK:         result.OK,
+		State:      result.State,
+		Error:      valueOrEmpty(result.Error),
+		DurationMs: result.DurationMs,
+	}
+}
+
+func (m *Monitor) failureCount(key streakKey) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.streaks[key].failures
+}
+
+func (m *Monitor) successCount(key streakKey) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.streaks[key].successes
+}
+
+func policyThreshold(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+func valueOrEmpty(ptr *string) string {
+	if ptr == nil {
+		return ""
+	}
+	return *ptr
+}
@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// udpQuietPeriod bounds how long a UDP probe without expected reply waits for an ICMP rejection.
+const udpQuietPeriod = time.Second
+
+// dialTCP opens and closes a TCP connection, returning the time it took to connect.
+func dialTCP(ctx context.Context, clock Clock, address string) (time.Duration, error) {
+	var dialer net.Dialer
+	started := clock.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	latency := clock.Now().Sub(started)
+	if err != nil {
+		return latency, err
+	}
+	_ = conn.Close()
+	return latency, nil
+}
+
+// exchangeUDP sends payload to address. When expectReply is set it waits for a datagram until the
+// context deadline; otherwise it only fails if the peer actively rejects the packet.
+func exchangeUDP(ctx context.Context, clock Clock, address string, payload []byte, expectReply bool) (time.Duration, []byte, error) {
+	var dialer net.Dialer
+	started := clock.Now()
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return clock.Now().Sub(started), nil, err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(15 * time.Second)
+	}
+	if !expectReply {
+		if quiet := time.Now().Add(udpQuietPeriod); quiet.Before(deadline) {
+			deadline = quiet
+		}
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(payload); err != nil {
+		return clock.Now().Sub(started), nil, err
+	}
+
+	buf := make([]byte, 2048)
+	n, err := conn.Read(buf)
+	latency := clock.Now().Sub(started)
+	if err != nil {
+		if !expectReply && errors.Is(err, os.ErrDeadlineExceeded) {
+			return latency, nil, nil
+		}
+		if expectReply && errors.Is(err, os.ErrDeadlineExceeded) {
+			return latency, nil, errors.New("no reply before timeout")
+		}
+		return latency, nil, err
+	}
+	return latency, buf[:n], nil
+}
+
+func (m *Monitor) checkPort(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+
+	var (
+		latency time.Duration
+		err     error
+	)
+	switch target.Type {
+	case models.TargetTypeUDP:
+		opts := models.UDPOptions{}
+		if target.UDP != nil {
+			opts = *target.UDP
+		}
+		var reply []byte
+		latency, reply, err = exchangeUDP(ctx, m.clock, address, []byte(opts.Payload), opts.ExpectReply)
+		if err == nil && opts.ExpectContains != "" && !bytes.Contains(reply, []byte(opts.ExpectContains)) {
+			err = fmt.Errorf("reply does not contain %q", opts.ExpectContains)
+		}
+	default:
+		latency, err = dialTCP(ctx, m.clock, address)
+	}
+	res.LatencyMs = int64(latency / time.Millisecond)
+	if err != nil {
+		return failResult(res, "unreachable", err.Error())
+	}
+
+	res.OK = true
+	res.State = "active"
+	return res
+}
@@ -0,0 +1,215 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// userHZ is the clock tick rate /proc reports CPU times in.
+const userHZ = 100
+
+// maxCommLength is the length the kernel truncates process names to.
+const maxCommLength = 15
+
+// procInfo holds the fields read for a process from /proc/<pid>.
+type procInfo struct {
+	pid      int
+	comm     string
+	cmdline  string
+	cpuTicks uint64
+	rssBytes uint64
+}
+
+// cpuSample remembers the CPU ticks of a target's processes at the previous check.
+type cpuSample struct {
+	at    time.Time
+	ticks map[int]uint64
+}
+
+func (m *Monitor) checkProcess(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	opts := *target.Process
+	var pattern *regexp.Regexp
+	if opts.Cmdline != "" {
+		var err error
+		if pattern, err = m.compilePattern(opts.Cmdline); err != nil {
+			return failResult(res, "unknown", fmt.Sprintf("invalid cmdline pattern: %v", err))
+		}
+	}
+	procs, err := matchProcesses(opts, pattern)
+	if err != nil {
+		return failResult(res, "unknown", err.Error())
+	}
+
+	var rss uint64
+	pids := make([]string, 0, len(procs))
+	for _, proc := range procs {
+		rss += proc.rssBytes
+		pids = append(pids, strconv.Itoa(proc.pid))
+	}
+	res.Metrics = map[string]models.Metric{
+		"instances": {Value: float64(len(procs))},
+		"rss":       {Value: float64(rss), Unit: "B"},
+	}
+	cpu, measured := m.recordCPU(target.ID, procs, m.clock.Now())
+	if measured {
+		res.Metrics["cpu"] = models.Metric{Value: cpu, Unit: "%"}
+	}
+	if len(procs) > 0 {
+		res.Output = fmt.Sprintf("%d process(es), pid %s", len(procs), strings.Join(pids, ", "))
+	}
+
+	switch {
+	case len(procs) == 0:
+		return failResult(res, "stopped", "no matching process is running")
+	case len(procs) < opts.MinInstances:
+		return failResult(res, "degraded", fmt.Sprintf("%d of at least %d instances running", len(procs), opts.MinInstances))
+	case opts.MaxInstances > 0 && len(procs) > opts.MaxInstances:
+		return failResult(res, "excess", fmt.Sprintf("%d instances running, at most %d allowed", len(procs), opts.MaxInstances))
+	case opts.MaxRSSMB > 0 && float64(rss) > opts.MaxRSSMB*1024*1024:
+		return failResult(res, "rss-limit", fmt.Sprintf("RSS %s exceeds %g MiB", formatBytes(rss), opts.MaxRSSMB))
+	case opts.MaxCPUPercent > 0 && measured && cpu > opts.MaxCPUPercent:
+		return failResult(res, "cpu-limit", fmt.Sprintf("CPU %.1f%% exceeds %g%%", cpu, opts.MaxCPUPercent))
+	}
+	res.OK = true
+	res.State = "running"
+	return res
+}
+
+// recordCPU stores the CPU ticks of procs and returns their combined CPU usage since the
+// previous check, counting only processes seen both times.
+func (m *Monitor) recordCPU(id string, procs []procInfo, now time.Time) (float64, bool) {
+	ticks := make(map[int]uint64, len(procs))
+	for _, proc := range procs {
+		ticks[proc.pid] = proc.cpuTicks
+	}
+
+	m.mu.Lock()
+	prev, seen := m.cpu[id]
+	m.cpu[id] = cpuSample{at: now, ticks: ticks}
+	m.mu.Unlock()
+
+	elapsed := now.Sub(prev.at).Seconds()
+	if !seen || elapsed <= 0 {
+		return 0, false
+	}
+	var used uint64
+	for pid, current := range ticks {
+		if before, ok := prev.ticks[pid]; ok && current >= before {
+			used += current - before
+		}
+	}
+	return float64(used) / userHZ / elapsed * 100, true
+}
+
+// matchProcesses returns the live processes satisfying every criterion set in opts; cmdline is
+// the compiled opts.Cmdline, or nil when it is not set.
+func matchProcesses(opts models.ProcessOptions, cmdline *regexp.Regexp) ([]procInfo, error) {
+	name := opts.Name
+	if len(name) > maxCommLength {
+		name = name[:maxCommLength]
+	}
+
+	var pids []int
+	if opts.Pidfile != "" {
+		pid, err := readPidfile(opts.Pidfile)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		pids = []int{pid}
+	} else {
+		entries, err := os.ReadDir(procRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if pid, err := strconv.Atoi(entry.Name()); err == nil && entry.IsDir() {
+				pids = append(pids, pid)
+			}
+		}
+		sort.Ints(pids)
+	}
+
+	self := os.Getpid()
+	var procs []procInfo
+	for _, pid := range pids {
+		if pid == self {
+			continue
+		}
+		proc, ok := readProc(pid)
+		if !ok {
+			continue
+		}
+		if name != "" && proc.comm != name {
+			continue
+		}
+		if cmdline != nil && !cmdline.MatchString(proc.cmdline) {
+			continue
+		}
+		procs = append(procs, proc)
+	}
+	return procs, nil
+}
+
+func readPidfile(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("pidfile %s: invalid pid", path)
+	}
+	return pid, nil
+}
+
+// readProc reads a process from /proc. ok is false for processes that exited or are zombies.
+func readProc(pid int) (procInfo, bool) {
+	dir := filepath.Join(procRoot, strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return procInfo{}, false
+	}
+	// The name is enclosed in parentheses and may itself contain spaces or parentheses.
+	open := strings.IndexByte(string(stat), '(')
+	end := strings.LastIndexByte(string(stat), ')')
+	if open < 0 || end < open {
+		return procInfo{}, false
+	}
+	// Fields after the name start with the state (field 3 in proc(5)).
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 22 || fields[0] == "Z" || fields[0] == "X" {
+		return procInfo{}, false
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+
+	cmdline, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+	return procInfo{
+		pid:      pid,
+		comm:     string(stat[open+1 : end]),
+		cmdline:  strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " ")),
+		cpuTicks: utime + stime,
+		rssBytes: rssPages * uint64(os.Getpagesize()),
+	}, true
+}
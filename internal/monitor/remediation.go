@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// remediationWatch counts a target's consecutive confirmed failures and remembers when its
+// remediation actions ran within the last hour.
+type remediationWatch struct {
+	failures int
+	actions  []time.Time
+}
+
+// remediate runs the remediation action of every target that kept failing and attaches the
+// outcome to its result. Blocked results neither count as failures nor reset the count, so a
+// dependency outage does not restart its dependents; neither do recovering results, whose raw
+// check already succeeded.
+func (m *Monitor) remediate(ctx context.Context, targets []models.Target, results []models.CheckResult) {
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		if t.Remediation == nil {
+			continue
+		}
+		attempt, due := m.remediationDue(t, results[i])
+		if !due {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, t models.Target) {
+			defer wg.Done()
+			results[i].Remediation = m.runRemediation(ctx, t, attempt)
+		}(i, t)
+	}
+	wg.Wait()
+}
+
+// remediationDue records a result and reports whether an action should run now, together with
+// the number of actions in the last hour including it.
+func (m *Monitor) remediationDue(t models.Target, res models.CheckResult) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := m.remediations[t.ID]
+	if w == nil {
+		w = &remediationWatch{}
+		m.remediations[t.ID] = w
+	}
+	switch {
+	case res.OK:
+		w.failures = 0
+		return 0, false
+	case res.State == "blocked", res.State == "recovering":
+		return 0, false
+	}
+	w.failures++
+
+	opts := t.Remediation
+	now := m.clock.Now()
+	recent := w.actions[:0]
+	for _, at := range w.actions {
+		if now.Sub(at) < time.Hour {
+			recent = append(recent, at)
+		}
+	}
+	w.actions = recent
+	if w.failures < opts.AfterFailures || len(w.actions) >= opts.MaxPerHour {
+		return 0, false
+	}
+	cooldown := time.Duration(opts.CooldownSeconds) * time.Second
+	if n := len(w.actions); n > 0 && now.Sub(w.actions[n-1]) < cooldown {
+		return 0, false
+	}
+	w.actions = append(w.actions, now)
+	return len(w.actions), true
+}
+
+// runRemediation restarts the target's unit or runs the configured command.
+func (m *Monitor) runRemediation(ctx context.Context, t models.Target, attempt int) *models.RemediationResult {
+	opts := t.Remediation
+	name, args := opts.Command, opts.Args
+	if opts.Action == models.RemediationRestart {
+		name, args = systemctlCommand(t, "restart", t.Service)
+	}
+	timeout := time.Duration(opts.TimeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := m.clock.Now()
+	stdout, stderr, err := m.runner.Run(runCtx, name, args...)
+	res := &models.RemediationResult{
+		Action:     opts.Action,
+		Command:    strings.Join(append([]string{name}, args...), " "),
+		Attempt:    attempt,
+		OK:         err == nil,
+		Output:     truncateOutput(string(stdout)),
+		StartedAt:  started.UTC(),
+		DurationMs: int64(m.clock.Now().Sub(started) / time.Millisecond),
+	}
+	if err != nil {
+		if opts.Action == models.RemediationRestart {
+			err = targetCommandError(runCtx, t, "systemctl", stdout, stderr, err)
+		} else {
+			err = commandError(runCtx, opts.Command, stdout, stderr, err)
+		}
+		res.Error = err.Error()
+		log.Printf("remediation %s of %s failed (attempt %d): %v", opts.Action, t.ID, attempt, err)
+	} else {
+		log.Printf("remediation %s of %s succeeded (attempt %d)", opts.Action, t.ID, attempt)
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+func TestRemediationRestartsWithinLimits(t *testing.T) {
+	target := models.Target{
+		ID:      "bot",
+		Name:    "Bot",
+		Type:    models.TargetTypeSystemd,
+		Service: "bot.service",
+		UseSudo: true,
+		Remediation: &models.RemediationOptions{
+			Action:          models.RemediationRestart,
+			AfterFailures:   2,
+			CooldownSeconds: 600,
+			MaxPerHour:      2,
+			TimeoutSeconds:  30,
+		},
+	}
+	var restarts []string
+	runner := &fakeRunner{script: func(_ context.Context, name string, args []string) ([]byte, []byte, error) {
+		if len(args) > 1 && args[1] == "restart" {
+			restarts = append(restarts, strings.Join(append([]string{name}, args...), " "))
+			return nil, nil, nil
+		}
+		return unitOutput("failed", "exit-code"), nil, nil
+	}}
+	m, _ := newTestMonitor(t, []models.Target{target}, runner)
+	clock := &fakeClock{now: testNow}
+	m.SetClock(clock)
+
+	// When each round runs after testNow, and the attempt expected in it (0 for none).
+	rounds := []struct {
+		at      time.Duration
+		attempt int
+	}{
+		{0, 0},                // first failure
+		{5 * time.Minute, 1},  // second failure: restart
+		{10 * time.Minute, 0}, // cooldown
+		{16 * time.Minute, 2}, // cooldown over
+		{30 * time.Minute, 0}, // two restarts within the hour
+		{66 * time.Minute, 2}, // first restart is older than an hour
+	}
+	for _, round := range rounds {
+		clock.now = testNow.Add(round.at)
+		entry, err := m.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		action := entry.Checks[0].Remediation
+		switch {
+		case round.attempt == 0 && action != nil:
+			t.Errorf("at %v: unexpected remediation %+v", round.at, action)
+		case round.attempt > 0 && (action == nil || !action.OK || action.Attempt != round.attempt):
+			t.Errorf("at %v: remediation = %+v, want successful attempt %d", round.at, action, round.attempt)
+		}
+	}
+	if len(restarts) != 3 || restarts[0] != "sudo systemctl restart bot.service" {
+		t.Errorf("restarts = %q, want three sudo systemctl restart bot.service", restarts)
+	}
+}
+
+func TestRemediationSkipsBlockedTargets(t *testing.T) {
+	targets := []models.Target{
+		{ID: "db", Name: "DB", Type: models.TargetTypeSystemd, Service: "db.service"},
+		{
+			ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service", DependsOn: []string{"db"},
+			Remediation: &models.RemediationOptions{Action: models.RemediationCommand, Command: "/usr/local/bin/fix-web", AfterFailures: 1, MaxPerHour: 5},
+		},
+	}
+	runner := &fakeRunner{script: func(_ context.Context, name string, _ []string) ([]byte, []byte, error) {
+		if name == "/usr/local/bin/fix-web" {
+			return nil, []byte("permission denied\n"), exitStatus(1)
+		}
+		return unitOutput("failed", "exit-code"), nil, nil
+	}}
+	m, _ := newTestMonitor(t, targets, runner)
+
+	entry, err := m.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if web := entry.Checks[1]; web.State != "blocked" || web.Remediation != nil {
+		t.Fatalf("web = %+v, want blocked without remediation", web)
+	}
+
+	// Run alone, a failing web target runs its command.
+	results := []models.CheckResult{{ID: "web", State: "failed"}}
+	m.remediate(context.Background(), []models.Target{targets[1]}, results)
+	action := results[0].Remediation
+	if action == nil || action.OK || action.Error != "permission denied" || action.Command != "/usr/local/bin/fix-web" {
+		t.Errorf("remediation = %+v, want failed fix-web command", action)
+	}
+}
+
+func TestRemediationIgnoresRecovery(t *testing.T) {
+	target := models.Target{
+		ID:           "bot",
+		Name:         "Bot",
+		Type:         models.TargetTypeSystemd,
+		Service:      "bot.service",
+		RecoverAfter: 3,
+		Remediation: &models.RemediationOptions{
+			Action:        models.RemediationRestart,
+			AfterFailures: 2,
+			MaxPerHour:    5,
+		},
+	}
+	active := "failed"
+	restarts := 0
+	runner := &fakeRunner{script: func(_ context.Context, _ string, args []string) ([]byte, []byte, error) {
+		if args[0] == "restart" {
+			restarts++
+			return nil, nil, nil
+		}
+		return unitOutput(active, "success"), nil, nil
+	}}
+	m, _ := newTestMonitor(t, []models.Target{target}, runner)
+
+	run := func() models.CheckResult {
+		entry, err := m.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		return entry.Checks[0]
+	}
+	run()
+	if res := run(); res.Remediation == nil || restarts != 1 {
+		t.Fatalf("second failure = %+v, want a restart", res)
+	}
+
+	// The unit is back up; the recovering samples must not count as further failures.
+	active = "active"
+	for i := 0; i < 2; i++ {
+		if res := run(); res.State != "recovering" || res.Remediation != nil {
+			t.Fatalf("recovering sample %d = %+v, want recovering without remediation", i, res)
+		}
+	}
+	if restarts != 1 {
+		t.Errorf("restarts = %d, want 1", restarts)
+	}
+}
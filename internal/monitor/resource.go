@@ -0,0 +1,281 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"jobmonitor/internal/models"
+)
+
+// procRoot is where host resource readings are taken from.
+var procRoot = "/proc"
+
+// resourceReading is a measured resource value together with the metrics stored for it.
+type resourceReading struct {
+	value   float64
+	summary string
+	metrics map[string]models.Metric
+}
+
+func (m *Monitor) checkResource(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	opts := *target.Resource
+	reading, err := readResource(target.Path, opts)
+	if err != nil {
+		return failResult(res, "unknown", err.Error())
+	}
+	res.Output = reading.summary
+	res.Metrics = reading.metrics
+
+	switch {
+	case opts.Critical > 0 && reading.value >= opts.Critical:
+		return failResult(res, "critical", fmt.Sprintf("%s (critical at %s)", reading.summary, formatThreshold(opts.Critical, opts.Kind)))
+	case opts.Warning > 0 && reading.value >= opts.Warning:
+		return warnResult(res, fmt.Sprintf("%s (warning at %s)", reading.summary, formatThreshold(opts.Warning, opts.Kind)))
+	}
+	res.OK = true
+	res.State = "ok"
+	return res
+}
+
+func readResource(path string, opts models.ResourceOptions) (resourceReading, error) {
+	switch opts.Kind {
+	case models.ResourceDisk:
+		return readDisk(path)
+	case models.ResourceInodes:
+		return readInodes(path)
+	case models.ResourceMemory:
+		return readMemory()
+	case models.ResourceSwap:
+		return readSwap()
+	case models.ResourceLoad:
+		return readLoad(opts.Window)
+	case models.ResourcePSI:
+		return readPressure(opts.Pressure, opts.Window)
+	default:
+		return resourceReading{}, fmt.Errorf("unknown resource kind %q", opts.Kind)
+	}
+}
+
+func formatThreshold(value float64, kind string) string {
+	if kind == models.ResourceLoad {
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64) + "%"
+}
+
+func readDisk(path string) (resourceReading, error) {
+	fs, err := statFilesystem(path)
+	if err != nil {
+		return resourceReading{}, err
+	}
+	// Like df, blocks reserved for root count neither as used nor as available.
+	used := fs.total - fs.free
+	percent := ratioPercent(used, used+fs.avail)
+	return resourceReading{
+		value:   percent,
+		summary: fmt.Sprintf("%s: %.1f%% used, %s free", path, percent, formatBytes(fs.avail)),
+		metrics: map[string]models.Metric{
+			"used":       {Value: percent, Unit: "%"},
+			"free_bytes": {Value: float64(fs.avail), Unit: "B"},
+		},
+	}, nil
+}
+
+func readInodes(path string) (resourceReading, error) {
+	fs, err := statFilesystem(path)
+	if err != nil {
+		return resourceReading{}, err
+	}
+	if fs.files == 0 {
+		// Filesystems such as btrfs allocate inodes dynamically and report no limit.
+		return resourceReading{summary: fmt.Sprintf("%s: no inode limit", path)}, nil
+	}
+	percent := ratioPercent(fs.files-fs.filesFree, fs.files)
+	return resourceReading{
+		value:   percent,
+		summary: fmt.Sprintf("%s: %.1f%% of inodes used, %d free", path, percent, fs.filesFree),
+		metrics: map[string]models.Metric{
+			"used":        {Value: percent, Unit: "%"},
+			"free_inodes": {Value: float64(fs.filesFree)},
+		},
+	}, nil
+}
+
+func readMemory() (resourceReading, error) {
+	info, err := readMeminfo()
+	if err != nil {
+		return resourceReading{}, err
+	}
+	total := info["MemTotal"]
+	if total == 0 {
+		return resourceReading{}, fmt.Errorf("MemTotal missing from meminfo")
+	}
+	available, ok := info["MemAvailable"]
+	if !ok {
+		// Kernels before 3.14 and some containers do not report MemAvailable.
+		available = info["MemFree"] + info["Buffers"] + info["Cached"]
+	}
+	if available > total {
+		available = total
+	}
+	percent := ratioPercent(total-available, total)
+	return resourceReading{
+		value:   percent,
+		summary: fmt.Sprintf("memory %.1f%% used, %s available", percent, formatBytes(available)),
+		metrics: map[string]models.Metric{
+			"used":            {Value: percent, Unit: "%"},
+			"available_bytes": {Value: float64(available), Unit: "B"},
+		},
+	}, nil
+}
+
+func readSwap() (resourceReading, error) {
+	info, err := readMeminfo()
+	if err != nil {
+		return resourceReading{}, err
+	}
+	total, free := info["SwapTotal"], info["SwapFree"]
+	if total == 0 {
+		return resourceReading{summary: "no swap configured"}, nil
+	}
+	percent := ratioPercent(total-free, total)
+	return resourceReading{
+		value:   percent,
+		summary: fmt.Sprintf("swap %.1f%% used, %s free", percent, formatBytes(free)),
+		metrics: map[string]models.Metric{
+			"used":       {Value: percent, Unit: "%"},
+			"free_bytes": {Value: float64(free), Unit: "B"},
+		},
+	}, nil
+}
+
+// readMeminfo returns the fields of /proc/meminfo in bytes.
+func readMeminfo() (map[string]uint64, error) {
+	f, err := os.Open(filepath.Join(procRoot, "meminfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 1 && fields[1] == "kB" {
+			value *= 1024
+		}
+		info[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if info["MemTotal"] == 0 {
+		return nil, fmt.Errorf("%s/meminfo: MemTotal missing", procRoot)
+	}
+	return info, nil
+}
+
+func readLoad(window int) (resourceReading, error) {
+	data, err := os.ReadFile(filepath.Join(procRoot, "loadavg"))
+	if err != nil {
+		return resourceReading{}, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return resourceReading{}, fmt.Errorf("%s/loadavg: unexpected format", procRoot)
+	}
+	index := map[int]int{1: 0, 5: 1, 15: 2}[window]
+	load, err := strconv.ParseFloat(fields[index], 64)
+	if err != nil {
+		return resourceReading{}, fmt.Errorf("%s/loadavg: %w", procRoot, err)
+	}
+	cpus := runtime.NumCPU()
+	perCPU := load / float64(cpus)
+	return resourceReading{
+		value:   perCPU,
+		summary: fmt.Sprintf("load %.2f over %d min on %d CPU(s), %.2f per CPU", load, window, cpus, perCPU),
+		metrics: map[string]models.Metric{
+			"load":         {Value: load},
+			"load_per_cpu": {Value: perCPU},
+		},
+	}, nil
+}
+
+// readPressure reads the share of time some tasks stalled on a resource from
+// /proc/pressure/<resource>, averaged over window seconds.
+func readPressure(resource string, window int) (resourceReading, error) {
+	path := filepath.Join(procRoot, "pressure", resource)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return resourceReading{}, err
+	}
+	key := fmt.Sprintf("avg%d", window)
+	metrics := make(map[string]models.Metric)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			name, raw, ok := strings.Cut(field, "=")
+			if !ok || name != key {
+				continue
+			}
+			if value, err := strconv.ParseFloat(raw, 64); err == nil {
+				metrics[fields[0]] = models.Metric{Value: value, Unit: "%"}
+			}
+		}
+	}
+	some, ok := metrics["some"]
+	if !ok {
+		return resourceReading{}, fmt.Errorf("%s: %s missing", path, key)
+	}
+	return resourceReading{
+		value:   some.Value,
+		summary: fmt.Sprintf("%s pressure %.2f%% over %ds", resource, some.Value, window),
+		metrics: metrics,
+	}, nil
+}
+
+func ratioPercent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
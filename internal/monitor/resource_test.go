@@ -0,0 +1,57 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadMemory(t *testing.T) {
+	tests := []struct {
+		name    string
+		meminfo string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name:    "mem available",
+			meminfo: "MemTotal: 1000 kB\nMemFree: 100 kB\nMemAvailable: 250 kB\nBuffers: 50 kB\nCached: 300 kB\n",
+			want:    75,
+		},
+		{
+			name:    "without mem available",
+			meminfo: "MemTotal: 1000 kB\nMemFree: 100 kB\nBuffers: 50 kB\nCached: 350 kB\n",
+			want:    50,
+		},
+		{
+			name:    "without mem total",
+			meminfo: "MemFree: 100 kB\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, "meminfo"), []byte(tt.meminfo), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			saved := procRoot
+			procRoot = root
+			defer func() { procRoot = saved }()
+
+			reading, err := readMemory()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("readMemory = %+v, want error", reading)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readMemory: %v", err)
+			}
+			if reading.value != tt.want {
+				t.Errorf("used = %.1f%%, want %.1f%%", reading.value, tt.want)
+			}
+		})
+	}
+}
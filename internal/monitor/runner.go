@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CommandRunner runs the external commands used by checks (systemctl, journalctl and command
+// targets). A failed command returns a non-nil error; errors that carry an exit status
+// implement ExitCode() int like *exec.ExitError.
+type CommandRunner interface {
+	Run(ctx context.Context, name string, args ...string) (stdout, stderr []byte, err error)
+}
+
+// Clock tells the monitor the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// execRunner runs commands with os/exec.
+type execRunner struct{}
+
+func (execRunner) Run(ctx context.Context, name string, args ...string) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for grandchildren that keep the output pipes open after a timeout.
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// exitCoder is implemented by errors that carry a process exit status.
+type exitCoder interface {
+	ExitCode() int
+}
+
+// commandError describes a failed command, preferring the text it printed over the bare exit
+// status. A command cut off by its context is reported as a timeout.
+func commandError(ctx context.Context, name string, stdout, stderr []byte, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s timed out: %w", name, ctx.Err())
+	}
+	msg := strings.TrimSpace(string(stderr))
+	if msg == "" {
+		msg = strings.TrimSpace(string(stdout))
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"log"
+	"time"
+
+	"jobmonitor/internal/models"
+	"jobmonitor/internal/schedule"
+)
+
+// MinTargetInterval is the shortest per-target check interval.
+const MinTargetInterval = 5 * time.Second
+
+// idleWait is used when no target has an upcoming run.
+const idleWait = 24 * time.Hour
+
+// scheduledTarget tracks when a target is checked next.
+type scheduledTarget struct {
+	target models.Target
+	every  time.Duration
+	cron   *schedule.Cron
+	next   time.Time
+}
+
+// newSchedule plans the next run of every target after now. Targets without their own
+// interval or schedule share the monitor interval and therefore run together.
+func (m *Monitor) newSchedule(now time.Time) []*scheduledTarget {
+	plan := make([]*scheduledTarget, 0, len(m.targets))
+	for _, t := range m.targets {
+		st := &scheduledTarget{target: t, every: m.interval}
+		switch {
+		case t.Schedule != "":
+			cron, err := schedule.Parse(t.Schedule)
+			if err != nil {
+				log.Printf("target %s: %v, using interval %s", t.ID, err, m.interval)
+				break
+			}
+			st.cron = cron
+		case t.IntervalSeconds > 0:
+			st.every = time.Duration(t.IntervalSeconds) * time.Second
+			if st.every < MinTargetInterval {
+				st.every = MinTargetInterval
+			}
+		}
+		if st.cron != nil {
+			st.next = st.cron.Next(now)
+		} else {
+			st.next = now.Add(st.every)
+		}
+		plan = append(plan, st)
+	}
+	return plan
+}
+
+// advance moves the target's next run past now, skipping runs missed while busy.
+func (s *scheduledTarget) advance(now time.Time) {
+	if s.cron != nil {
+		s.next = s.cron.Next(now)
+		return
+	}
+	for !s.next.After(now) {
+		s.next = s.next.Add(s.every)
+	}
+}
+
+// dueTargets returns targets whose next run is not after now, in configured order.
+func dueTargets(plan []*scheduledTarget, now time.Time) []models.Target {
+	var due []models.Target
+	for _, st := range plan {
+		if st.next.IsZero() || st.next.After(now) {
+			continue
+		}
+		due = append(due, st.target)
+		st.advance(now)
+	}
+	return due
+}
+
+// nextRun returns the earliest upcoming run across the plan.
+func nextRun(plan []*scheduledTarget) time.Time {
+	var earliest time.Time
+	for _, st := range plan {
+		if st.next.IsZero() {
+			continue
+		}
+		if earliest.IsZero() || st.next.Before(earliest) {
+			earliest = st.next
+		}
+	}
+	if earliest.IsZero() {
+		return time.Now().Add(idleWait)
+	}
+	return earliest
+}
@@ -0,0 +1,82 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"jobmonitor/internal/models"
+)
+
+// sshFailureStatus is the exit status of ssh when it fails itself rather than the remote command.
+const sshFailureStatus = 255
+
+// errUnreachable marks failures to reach a remote host, as opposed to failures of the unit.
+var errUnreachable = errors.New("host unreachable")
+
+// remoteCommand wraps a command line so that it runs on the target's host over ssh. Targets
+// without a host run it locally.
+func remoteCommand(target models.Target, name string, args []string) (string, []string) {
+	if target.Host == "" {
+		return name, args
+	}
+	opts := models.SSHOptions{}
+	if target.SSH != nil {
+		opts = *target.SSH
+	}
+	timeout := int(checkTimeout(target).Seconds())
+	sshArgs := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=" + strconv.Itoa(timeout),
+	}
+	if opts.IdentityFile != "" {
+		sshArgs = append(sshArgs, "-i", opts.IdentityFile, "-o", "IdentitiesOnly=yes")
+	}
+	if opts.KnownHostsFile != "" {
+		sshArgs = append(sshArgs, "-o", "UserKnownHostsFile="+opts.KnownHostsFile)
+	}
+	if target.Port > 0 {
+		sshArgs = append(sshArgs, "-p", strconv.Itoa(target.Port))
+	}
+	if opts.User != "" {
+		sshArgs = append(sshArgs, "-l", opts.User)
+	}
+	// ssh hands the remote command to the login shell as one string.
+	remote := make([]string, 0, len(args)+1)
+	for _, arg := range append([]string{name}, args...) {
+		remote = append(remote, shellQuote(arg))
+	}
+	sshArgs = append(sshArgs, target.Host, "--", strings.Join(remote, " "))
+	return "ssh", sshArgs
+}
+
+// shellQuote quotes s for a POSIX shell unless it only holds characters that need no quoting.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%_-+=:,./", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// targetCommandError describes a failed systemctl or journalctl run. For remote targets an
+// ssh failure is reported as errUnreachable.
+func targetCommandError(ctx context.Context, target models.Target, name string, stdout, stderr []byte, err error) error {
+	cmdErr := commandError(ctx, name, stdout, stderr, err)
+	var exitErr exitCoder
+	if target.Host != "" && ctx.Err() == nil && errors.As(err, &exitErr) && exitErr.ExitCode() == sshFailureStatus {
+		return fmt.Errorf("%w: ssh %s: %v", errUnreachable, target.Host, cmdErr)
+	}
+	return cmdErr
+}
+
+// unitErrorState returns the state recorded when a unit could not be read.
+func unitErrorState(err error) string {
+	if errors.Is(err, errUnreachable) {
+		return "unreachable"
+	}
+	return "unknown"
+}
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"jobmonitor/internal/models"
+)
+
+func TestRemoteSystemctlCommand(t *testing.T) {
+	target := models.Target{
+		Host:           "db1.example.com",
+		Port:           2222,
+		UseSudo:        true,
+		TimeoutSeconds: 5,
+		SSH:            &models.SSHOptions{User: "monitor", IdentityFile: "/etc/jobmonitor/id_ed25519"},
+	}
+	name, args := systemctlCommand(target, "show", "my app.service", "--property=ActiveState")
+	want := []string{
+		"-o", "BatchMode=yes", "-o", "ConnectTimeout=5",
+		"-i", "/etc/jobmonitor/id_ed25519", "-o", "IdentitiesOnly=yes",
+		"-p", "2222", "-l", "monitor", "db1.example.com", "--",
+		"sudo systemctl show 'my app.service' --property=ActiveState",
+	}
+	if name != "ssh" || !reflect.DeepEqual(args, want) {
+		t.Errorf("systemctlCommand = %q %q, want ssh %q", name, args, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"web.service":      "web.service",
+		"--since=@1700000": "--since=@1700000",
+		"":                 "''",
+		"a b":              "'a b'",
+		"it's":             `'it'\''s'`,
+		"$(reboot)":        "'$(reboot)'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRemoteUnitStates(t *testing.T) {
+	target := models.Target{ID: "db", Name: "DB", Type: models.TargetTypeSystemd, Service: "db.service", Host: "db1"}
+	tests := []struct {
+		name   string
+		stderr string
+		err    error
+		want   string
+	}{
+		{name: "connection refused", stderr: "ssh: connect to host db1 port 22: Connection refused\n", err: exitStatus(sshFailureStatus), want: "unreachable"},
+		{name: "remote failure", stderr: "Failed to connect to bus: No such file or directory\n", err: exitStatus(1), want: "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+				return nil, []byte(tt.stderr), tt.err
+			}}
+			m, _ := newTestMonitor(t, []models.Target{target}, runner)
+
+			res := m.runCheck(context.Background(), target)
+			if res.OK || res.State != tt.want || res.Error == nil {
+				t.Errorf("check = %+v, want state %q with an error", res, tt.want)
+			}
+			if calls := runner.commands(); len(calls) != 1 || calls[0][0] != "ssh" {
+				t.Errorf("commands = %q, want one ssh call", calls)
+			}
+		})
+	}
+}
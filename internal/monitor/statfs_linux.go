@@ -0,0 +1,27 @@
+package monitor
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// filesystemStats holds the block and inode counters of a mounted filesystem.
+type filesystemStats struct {
+	total, free, avail uint64
+	files, filesFree   uint64
+}
+
+func statFilesystem(path string) (filesystemStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return filesystemStats{}, fmt.Errorf("statfs %s: %w", path, err)
+	}
+	size := uint64(st.Bsize)
+	return filesystemStats{
+		total:     st.Blocks * size,
+		free:      st.Bfree * size,
+		avail:     st.Bavail * size,
+		files:     st.Files,
+		filesFree: st.Ffree,
+	}, nil
+}
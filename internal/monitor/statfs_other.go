@@ -0,0 +1,18 @@
+//go:build !linux
+
+package monitor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// filesystemStats holds the block and inode counters of a mounted filesystem.
+type filesystemStats struct {
+	total, free, avail uint64
+	files, filesFree   uint64
+}
+
+func statFilesystem(path string) (filesystemStats, error) {
+	return filesystemStats{}, fmt.Errorf("statfs %s: not supported on %s", path, runtime.GOOS)
+}
@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// unitProperties lists the properties requested from `systemctl show`.
+var unitProperties = []string{
+	"ActiveState",
+	"SubState",
+	"Result",
+	"NRestarts",
+	"ExecMainPID",
+	"ExecMainStatus",
+	"ActiveEnterTimestamp",
+	"MemoryCurrent",
+	"CPUUsageNSec",
+	"ExecMainStartTimestamp",
+	"ExecMainExitTimestamp",
+	"LastTriggerUSec",
+	"NextElapseUSecRealtime",
+}
+
+// systemdTimestampLayout matches the default timestamp format printed by systemctl.
+const systemdTimestampLayout = "Mon 2006-01-02 15:04:05 MST"
+
+// systemctlCommand builds the command line for systemctl, prefixed with sudo when requested and
+// run over ssh for targets on another host.
+func systemctlCommand(target models.Target, args ...string) (string, []string) {
+	if target.UseSudo {
+		return remoteCommand(target, "sudo", append([]string{"systemctl"}, args...))
+	}
+	return remoteCommand(target, "systemctl", args)
+}
+
+func (m *Monitor) checkSystemd(ctx context.Context, target models.Target) models.CheckResult {
+	if target.Kind == models.UnitKindOneshot || target.Kind == models.UnitKindTimer {
+		return m.checkJob(ctx, target)
+	}
+
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	unit, err := m.unitStatus(ctx, target)
+	if err != nil {
+		msg := err.Error()
+		res.State = unitErrorState(err)
+		res.Error = &msg
+		return res
+	}
+
+	res = unitResult(res, unit)
+	if restarted := m.restartsSince(target.ID, unit.NRestarts); restarted > 0 && res.OK {
+		// A restart between samples would otherwise look like uninterrupted uptime.
+		msg := fmt.Sprintf("restarted %d time(s) since last check", restarted)
+		res.OK = false
+		res.State = "restarted"
+		res.Error = &msg
+	}
+	return res
+}
+
+// unitStatus reads unit properties from the configured backend, falling back to systemctl.
+// Units on remote hosts are always read with systemctl over ssh.
+func (m *Monitor) unitStatus(ctx context.Context, target models.Target) (models.UnitStatus, error) {
+	if m.units != nil && target.Host == "" {
+		unit, err := m.units.UnitStatus(ctx, target.Service)
+		if err == nil {
+			return unit, nil
+		}
+		log.Printf("systemd backend failed for %s, falling back to systemctl: %v", target.Service, err)
+	}
+	return m.systemctlShow(ctx, target)
+}
+
+func (m *Monitor) systemctlShow(ctx context.Context, target models.Target) (models.UnitStatus, error) {
+	args := []string{"show", target.Service, "--no-pager", "--property=" + strings.Join(unitProperties, ",")}
+	cmdName, cmdArgs := systemctlCommand(target, args...)
+	stdout, stderr, err := m.runner.Run(ctx, cmdName, cmdArgs...)
+	if err != nil {
+		return models.UnitStatus{}, targetCommandError(ctx, target, "systemctl", stdout, stderr, err)
+	}
+	return parseUnitStatus(string(stdout)), nil
+}
+
+// unitResult fills a check result from unit properties.
+func unitResult(res models.CheckResult, unit models.UnitStatus) models.CheckResult {
+	res.Unit = &unit
+	state := unit.ActiveState
+	if state == "" {
+		state = "unknown"
+	}
+	res.State = state
+	res.OK = strings.EqualFold(state, "active")
+	if !res.OK {
+		msg := state
+		if unit.Result != "" && unit.Result != "success" {
+			msg = fmt.Sprintf("%s (result: %s)", state, unit.Result)
+		}
+		res.Error = &msg
+	}
+	return res
+}
+
+// restartsSince returns how many restarts happened since the last recorded restart counter of
+// the target.
+func (m *Monitor) restartsSince(id string, count int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prev, seen := m.restarts[id]
+	if !seen || count <= prev {
+		return 0
+	}
+	return count - prev
+}
+
+// recordRestarts stores the restart counters of results that were reported, by a regular sample
+// or by a watch poll or unit event, so that every restart is reported exactly once.
+func (m *Monitor) recordRestarts(results []models.CheckResult) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, result := range results {
+		switch {
+		case result.Unit != nil:
+			m.restarts[result.ID] = result.Unit.NRestarts
+		case result.Container != nil:
+			m.restarts[result.ID] = result.Container.RestartCount
+		}
+	}
+}
+
+func parseUnitStatus(output string) models.UnitStatus {
+	var unit models.UnitStatus
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "ActiveState":
+			unit.ActiveState = value
+		case "SubState":
+			unit.SubState = value
+		case "Result":
+			unit.Result = value
+		case "NRestarts":
+			unit.NRestarts, _ = strconv.Atoi(value)
+		case "ExecMainPID":
+			unit.MainPID, _ = strconv.Atoi(value)
+		case "ExecMainStatus":
+			unit.MainStatus, _ = strconv.Atoi(value)
+		case "ActiveEnterTimestamp":
+			unit.ActiveSince = parseUnitTimestamp(value)
+		case "MemoryCurrent":
+			unit.MemoryBytes = parseUnitCounter(value)
+		case "CPUUsageNSec":
+			unit.CPUUsageNSec = parseUnitCounter(value)
+		case "ExecMainStartTimestamp":
+			unit.LastRunStart = parseUnitTimestamp(value)
+		case "ExecMainExitTimestamp":
+			unit.LastRunEnd = parseUnitTimestamp(value)
+		case "LastTriggerUSec":
+			unit.LastTrigger = parseUnitTimestamp(value)
+		case "NextElapseUSecRealtime":
+			unit.NextElapse = parseUnitTimestamp(value)
+		}
+	}
+	return unit
+}
+
+// parseUnitTimestamp converts a timestamp printed by systemctl, returning nil for "n/a" or empty values.
+func parseUnitTimestamp(value string) *time.Time {
+	ts, err := time.ParseInLocation(systemdTimestampLayout, value, time.Local)
+	if err != nil {
+		return nil
+	}
+	utc := ts.UTC()
+	return &utc
+}
+
+// parseUnitCounter converts a systemd counter, treating "[not set]" and UINT64_MAX as unavailable.
+func parseUnitCounter(value string) uint64 {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || n == ^uint64(0) {
+		return 0
+	}
+	return n
+}
+
+// watchUnits records unit state transitions reported by the backend as event entries.
+func (m *Monitor) watchUnits(ctx context.Context) {
+	byUnit := make(map[string][]models.Target)
+	units := make([]string, 0, len(m.targets))
+	for _, target := range m.targets {
+		if target.Type != models.TargetTypeSystemd || target.Host != "" {
+			continue
+		}
+		if _, ok := byUnit[target.Service]; !ok {
+			units = append(units, target.Service)
+		}
+		byUnit[target.Service] = append(byUnit[target.Service], target)
+	}
+	if len(units) == 0 {
+		return
+	}
+
+	err := m.units.Watch(ctx, units, func(unit string, status models.UnitStatus) {
+		for _, target := range byUnit[unit] {
+			if target.Kind == models.UnitKindOneshot || target.Kind == models.UnitKindTimer {
+				// Job units are judged by their last run, not their active state.
+				m.recordEvent(target, m.runCheck(ctx, target))
+				continue
+			}
+			m.recordTransition(ctx, target, status)
+		}
+	})
+	if err != nil {
+		log.Printf("systemd watch stopped: %v", err)
+	}
+}
+
+// recordTransition appends an event entry when a unit's state differs from the last one seen.
+// Failing transitions of targets with a journal check carry the unit's recent journal lines.
+func (m *Monitor) recordTransition(ctx context.Context, target models.Target, unit models.UnitStatus) {
+	res := unitResult(models.CheckResult{ID: target.ID, Name: target.Name}, unit)
+	if target.Journal != nil && !res.OK {
+		journalCtx, cancel := context.WithTimeout(ctx, checkTimeout(target))
+		res = m.checkJournal(journalCtx, target, res)
+		cancel()
+	}
+	m.recordEvent(target, res)
+}
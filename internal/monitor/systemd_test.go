@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+func TestSystemctlCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		useSudo  bool
+		wantName string
+		wantArgs []string
+	}{
+		{name: "plain", wantName: "systemctl", wantArgs: []string{"show", "web.service"}},
+		{name: "sudo", useSudo: true, wantName: "sudo", wantArgs: []string{"systemctl", "show", "web.service"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args := systemctlCommand(models.Target{UseSudo: tt.useSudo}, "show", "web.service")
+			if name != tt.wantName || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("systemctlCommand = %q %q, want %q %q", name, args, tt.wantName, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		stdout string
+		stderr string
+		err    error
+		want   string
+	}{
+		{name: "stderr", ctx: context.Background(), stdout: "ignored", stderr: "  Unit web.service not found.\n", err: exitStatus(1), want: "Unit web.service not found."},
+		{name: "stdout", ctx: context.Background(), stdout: "Access denied\n", err: exitStatus(1), want: "Access denied"},
+		{name: "exit status", ctx: context.Background(), err: exitStatus(4), want: "exit status 4"},
+		{name: "start failure", ctx: context.Background(), err: errors.New("exec: \"systemctl\": executable file not found in $PATH"), want: "exec: \"systemctl\": executable file not found in $PATH"},
+		{name: "timeout", ctx: expired, stderr: "partial", err: errors.New("signal: killed"), want: "systemctl timed out: context deadline exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := commandError(tt.ctx, "systemctl", []byte(tt.stdout), []byte(tt.stderr), tt.err)
+			if got.Error() != tt.want {
+				t.Errorf("commandError = %q, want %q", got.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnitStatus(t *testing.T) {
+	output := "ActiveState=active\nSubState=running\nResult=success\nNRestarts=3\nExecMainPID=1234\n" +
+		"ExecMainStatus=0\nMemoryCurrent=[not set]\nCPUUsageNSec=18446744073709551615\nActiveEnterTimestamp=n/a\n"
+	unit := parseUnitStatus(output)
+
+	want := models.UnitStatus{
+		ActiveState: "active",
+		SubState:    "running",
+		Result:      "success",
+		NRestarts:   3,
+		MainPID:     1234,
+	}
+	if !reflect.DeepEqual(unit, want) {
+		t.Errorf("parseUnitStatus = %+v, want %+v", unit, want)
+	}
+}
+
+func TestRestartBetweenChecks(t *testing.T) {
+	target := models.Target{ID: "web", Name: "Web", Type: models.TargetTypeSystemd, Service: "web.service"}
+	restarts := "0"
+	runner := &fakeRunner{script: func(context.Context, string, []string) ([]byte, []byte, error) {
+		return []byte("ActiveState=active\nResult=success\nNRestarts=" + restarts + "\n"), nil, nil
+	}}
+	m, store := newTestMonitor(t, []models.Target{target}, runner)
+	sample := func() models.CheckResult {
+		t.Helper()
+		entry, err := m.RunOnce(context.Background())
+		if err != nil {
+			t.Fatalf("RunOnce: %v", err)
+		}
+		return entry.Checks[0]
+	}
+
+	if res := sample(); !res.OK || res.State != "active" {
+		t.Fatalf("first sample = %+v, want active", res)
+	}
+	restarts = "2"
+	res := sample()
+	if res.OK || res.State != "restarted" {
+		t.Fatalf("second sample = %+v, want restarted", res)
+	}
+	if want := "restarted 2 time(s) since last check"; res.Error == nil || *res.Error != want {
+		t.Errorf("error = %v, want %q", res.Error, want)
+	}
+	if res := sample(); !res.OK || res.State != "active" {
+		t.Fatalf("third sample = %+v, want active", res)
+	}
+
+	// A restart seen by a watch poll is recorded as an event and not reported again.
+	restarts = "3"
+	watch := func() models.CheckResult {
+		result := m.runCheck(context.Background(), target)
+		m.recordEvent(target, result)
+		return result
+	}
+	if res := watch(); res.State != "restarted" {
+		t.Fatalf("watch poll = %+v, want restarted", res)
+	}
+	if res := watch(); !res.OK || res.State != "active" {
+		t.Fatalf("next watch poll = %+v, want active", res)
+	}
+	if res := sample(); !res.OK || res.State != "active" {
+		t.Fatalf("sample after watch polls = %+v, want active", res)
+	}
+	var events []string
+	for _, entry := range store.History() {
+		if entry.Event {
+			events = append(events, entry.Checks[0].State)
+		}
+	}
+	if len(events) != 2 || events[0] != "restarted" || events[1] != "active" {
+		t.Fatalf("events = %v, want [restarted active]", events)
+	}
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+// defaultCertWarnDays is used when a target does not set tls.warn_days.
+const defaultCertWarnDays = 14
+
+func (m *Monitor) checkTLS(ctx context.Context, target models.Target) models.CheckResult {
+	res := models.CheckResult{
+		ID:   target.ID,
+		Name: target.Name,
+		OK:   false,
+	}
+
+	opts := models.TLSOptions{}
+	if target.TLS != nil {
+		opts = *target.TLS
+	}
+	port := target.Port
+	if port <= 0 {
+		port = 443
+	}
+	serverName := opts.ServerName
+	if serverName == "" {
+		serverName = target.Host
+	}
+
+	dialer := &tls.Dialer{
+		Config: &tls.Config{
+			ServerName: serverName,
+			// The chain is verified below so that invalid certificates can still be described.
+			InsecureSkipVerify: true,
+		},
+	}
+	started := m.clock.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(target.Host, strconv.Itoa(port)))
+	res.LatencyMs = int64(m.clock.Now().Sub(started) / time.Millisecond)
+	if err != nil {
+		return failResult(res, "unreachable", err.Error())
+	}
+	state := conn.(*tls.Conn).ConnectionState()
+	_ = conn.Close()
+
+	chainErr := verifyChain(state.PeerCertificates, serverName)
+	return evaluateCertificate(res, state.PeerCertificates, chainErr, opts, m.clock.Now())
+}
+
+func verifyChain(certs []*x509.Certificate, serverName string) error {
+	if len(certs) == 0 {
+		return fmt.Errorf("no certificate presented")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// evaluateCertificate describes the leaf certificate in res and judges its expiry and validity
+// as of now.
+func evaluateCertificate(res models.CheckResult, certs []*x509.Certificate, chainErr error, opts models.TLSOptions, now time.Time) models.CheckResult {
+	if len(certs) == 0 {
+		return failResult(res, "invalid", "no certificate presented")
+	}
+	leaf := certs[0]
+	daysLeft := int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+	info := &models.TLSInfo{
+		Subject:    leaf.Subject.String(),
+		Issuer:     leaf.Issuer.String(),
+		DNSNames:   leaf.DNSNames,
+		NotAfter:   leaf.NotAfter.UTC(),
+		DaysLeft:   daysLeft,
+		ChainValid: chainErr == nil,
+	}
+	if chainErr != nil {
+		info.VerifyError = chainErr.Error()
+	}
+	res.TLS = info
+	if res.Metrics == nil {
+		res.Metrics = make(map[string]models.Metric)
+	}
+	res.Metrics["cert_days_left"] = models.Metric{Value: float64(daysLeft), Unit: "d"}
+
+	warnDays := opts.WarnDays
+	if warnDays <= 0 {
+		warnDays = defaultCertWarnDays
+	}
+	switch {
+	case now.After(leaf.NotAfter):
+		return failResult(res, "expired", fmt.Sprintf("certificate expired on %s", leaf.NotAfter.UTC().Format(time.RFC3339)))
+	case chainErr != nil:
+		return failResult(res, "invalid", chainErr.Error())
+	case daysLeft < warnDays:
+		return warnResult(res, fmt.Sprintf("certificate expires in %d days", daysLeft))
+	}
+	res.OK = true
+	res.State = "valid"
+	return res
+}
@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"jobmonitor/internal/models"
+)
+
+func TestEvaluateCertificate(t *testing.T) {
+	notAfter := testNow.Add(30 * 24 * time.Hour)
+	certs := []*x509.Certificate{{NotAfter: notAfter}}
+	tests := []struct {
+		name      string
+		now       time.Time
+		chainErr  error
+		wantState string
+		wantDays  int
+	}{
+		{"valid", testNow, nil, "valid", 30},
+		{"within warning", notAfter.Add(-10 * 24 * time.Hour), nil, "warning", 10},
+		{"expired", notAfter.Add(time.Hour), nil, "expired", -1},
+		{"untrusted", testNow, errors.New("unknown authority"), "invalid", 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := evaluateCertificate(models.CheckResult{ID: "web"}, certs, tt.chainErr, models.TLSOptions{}, tt.now)
+			if res.State != tt.wantState || res.OK != (tt.wantState == "valid" || tt.wantState == "warning") {
+				t.Errorf("state = %q (ok %v), want %q", res.State, res.OK, tt.wantState)
+			}
+			if res.TLS == nil || res.TLS.DaysLeft != tt.wantDays {
+				t.Errorf("tls = %+v, want %d days left", res.TLS, tt.wantDays)
+			}
+		})
+	}
+}
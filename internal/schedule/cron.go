@@ -0,0 +1,210 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSearch bounds how far ahead Next looks for a matching minute.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Cron is a parsed five-field cron expression (minute hour day-of-month month day-of-week).
+type Cron struct {
+	minute, hour, dom, month, dow uint64
+	hourStar, domStar, dowStar    bool
+}
+
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+var fields = [5]field{
+	{0, 59, nil},        // minute
+	{0, 23, nil},        // hour
+	{1, 31, nil},        // day of month
+	{1, 12, monthNames}, // month
+	{0, 7, dayNames},    // day of week, 0 and 7 are Sunday
+}
+
+// value parses a number or, for month and day-of-week, a three-letter name.
+func (f field) value(raw string) (int, error) {
+	if n, ok := f.names[strings.ToLower(raw)]; ok {
+		return n, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+// Parse parses a standard five-field cron expression or one of the @hourly style descriptors.
+func Parse(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	if replacement, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = replacement
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields", expr)
+	}
+
+	var masks [5]uint64
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %w", expr, err)
+		}
+		masks[i] = mask
+	}
+	// Fold Sunday=7 onto 0.
+	if masks[4]&(1<<7) != 0 {
+		masks[4] |= 1
+	}
+
+	// Like cron, a field starting with * (including */n) counts as unrestricted.
+	return &Cron{
+		minute:   masks[0],
+		hour:     masks[1],
+		dom:      masks[2],
+		month:    masks[3],
+		dow:      masks[4],
+		hourStar: strings.HasPrefix(parts[1], "*"),
+		domStar:  strings.HasPrefix(parts[2], "*"),
+		dowStar:  strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseField(raw string, f field) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(raw, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", item)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, fmt.Errorf("invalid range %q", item)
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, fmt.Errorf("invalid range %q", item)
+			}
+		default:
+			n, err := f.value(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", item)
+			}
+			lo = n
+			if !hasStep {
+				hi = n
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range %d-%d", item, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			mask |= 1 << uint(v)
+		}
+	}
+	return mask, nil
+}
+
+// Next returns the first matching minute strictly after t, or the zero time if none exists.
+// Times skipped by a daylight saving jump do not fire; times repeated when clocks go back fire
+// once unless the hour field is unrestricted.
+func (c *Cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = wallClock(t.Year(), t.Month()+1, 1, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = wallClock(t.Year(), t.Month(), t.Day()+1, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = wallClock(t.Year(), t.Month(), t.Day(), t.Hour()+1, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 || (!c.hourStar && repeatedWallClock(t)) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Count returns how many times the expression fires in (start, end].
+func (c *Cron) Count(start, end time.Time) int {
+	count := 0
+	for t := c.Next(start); !t.IsZero() && !t.After(end); t = c.Next(t) {
+		count++
+	}
+	return count
+}
+
+// dayMatches applies cron's rule that a restricted day-of-month and day-of-week match if either does.
+func (c *Cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dowMatch
+	case c.dowStar:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// repeatedWallClock reports whether the wall clock already showed t's hour and minute an hour
+// earlier, which happens during the hour repeated when daylight saving time ends.
+func repeatedWallClock(t time.Time) bool {
+	earlier := t.Add(-time.Hour)
+	return earlier.Hour() == t.Hour() && earlier.Minute() == t.Minute()
+}
+
+// wallClock returns the first instant the wall clock in loc shows the given hour. time.Date may
+// pick the second one when the hour is repeated.
+func wallClock(year int, month time.Month, day, hour int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, 0, 0, 0, loc)
+	if repeatedWallClock(t) {
+		t = t.Add(-time.Hour)
+	}
+	return t
+}
@@ -0,0 +1,152 @@
+package schedule
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func mustParse(t *testing.T, expr string) *Cron {
+	t.Helper()
+	c, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", expr, err)
+	}
+	return c
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"* * * * mon-",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	from := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC) // a Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"5-10/2 * * * *", time.Date(2025, 1, 15, 10, 9, 0, 0, time.UTC)},
+		{"0,30 9-17 * * *", time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"0 8 * * *", time.Date(2025, 1, 16, 8, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * MON-FRI", time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * sat", time.Date(2025, 1, 18, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 MAR *", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan-feb *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// Month boundaries: skip months without the day, and roll over the year.
+		{"0 0 31 * *", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 * *", time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 1 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.expr).Next(from); !got.Equal(tt.want) {
+			t.Errorf("%q: Next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestNextAcrossMonthEnd(t *testing.T) {
+	c := mustParse(t, "0 0 31 * *")
+	got := c.Next(time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Next = %v, want %v", got, want)
+	}
+	if got := mustParse(t, "0 0 30 2 *").Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("impossible date fired at %v", got)
+	}
+}
+
+func TestDayOfMonthOrDayOfWeek(t *testing.T) {
+	from := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want []int // days of January 2025 that fire; January 1st is a Wednesday
+	}{
+		// Both restricted: either one matches.
+		{"0 0 13 * FRI", []int{3, 10, 13, 17, 24, 31}},
+		// Only one restricted: that one decides.
+		{"0 0 13 * *", []int{13}},
+		{"0 0 * * FRI", []int{3, 10, 17, 24, 31}},
+		// A stepped * still counts as unrestricted, so only the other field applies.
+		{"0 0 */10 * FRI", []int{3, 10, 17, 24, 31}},
+		{"0 0 1-7 * */2", []int{1, 2, 3, 4, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		c := mustParse(t, tt.expr)
+		var got []int
+		for next := c.Next(from); next.Before(from.AddDate(0, 1, 0)); next = c.Next(next) {
+			got = append(got, next.Day())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%q fired on %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestNextAcrossDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Warsaw")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	// 2025-03-30: clocks jump from 02:00 to 03:00, so 02:30 does not exist that day.
+	got := mustParse(t, "30 2 * * *").Next(time.Date(2025, 3, 30, 0, 0, 0, 0, loc))
+	if want := time.Date(2025, 3, 31, 2, 30, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("spring forward: Next = %v, want %v", got, want)
+	}
+	if got := mustParse(t, "@hourly").Count(time.Date(2025, 3, 30, 0, 0, 0, 0, loc), time.Date(2025, 3, 31, 0, 0, 0, 0, loc)); got != 23 {
+		t.Errorf("spring forward: hourly count = %d, want 23", got)
+	}
+
+	// 2025-10-26: clocks go back from 03:00 to 02:00, so 02:30 happens twice.
+	start := time.Date(2025, 10, 26, 0, 0, 0, 0, loc)
+	end := time.Date(2025, 10, 27, 0, 0, 0, 0, loc)
+	if got := mustParse(t, "30 2 * * *").Count(start, end); got != 1 {
+		t.Errorf("fall back: daily count = %d, want 1", got)
+	}
+	if got := mustParse(t, "@hourly").Count(start, end); got != 25 {
+		t.Errorf("fall back: hourly count = %d, want 25", got)
+	}
+}
+
+func TestCount(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expr string
+		end  time.Time
+		want int
+	}{
+		{"*/15 * * * *", start.Add(time.Hour), 4},
+		{"@hourly", start.Add(24 * time.Hour), 24},
+		{"0 9 * * MON-FRI", start.AddDate(0, 0, 14), 10},
+		{"@monthly", start.AddDate(1, 0, 0), 12},
+		{"0 0 1 1 *", start.AddDate(0, 6, 0), 0},
+		{"@daily", start, 0},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.expr).Count(start, tt.end); got != tt.want {
+			t.Errorf("%q: Count = %d, want %d", tt.expr, got, tt.want)
+		}
+	}
+}
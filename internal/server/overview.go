@@ -12,6 +12,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"jobmonitor/internal/cluster"
+	timeline "jobmonitor/internal/history"
 	"jobmonitor/internal/models"
 )
 
@@ -24,6 +25,7 @@ const (
 	overviewStateUnknown    = "unknown"
 	overviewStateOK         = "ok"
 	overviewStateIssue      = "issue"
+	overviewStateBlocked    = "blocked"
 	overviewConnectivityID  = "connectivity"
 	overviewConnectivityKey = "connectivity"
 )
@@ -137,18 +139,32 @@ func (s *Server) overviewConnectivityItems(snapshot cluster.ClusterSnapshot, buc
 	items := make([]overviewItem, 0, len(snapshot.Nodes))
 	for _, node := range snapshot.Nodes {
 		nodeID := strings.TrimSpace(node.Node.ID)
-		items = append(items, overviewItem{
-			ID:       buildConnectivityID(nodeID),
-			Name:     "Connectivity",
-			Kind:     overviewConnectivityKey,
-			NodeID:   nodeID,
-			NodeName: fallbackName(node.Node),
-			Buckets:  buildConnectivityBuckets(buckets, node.ConnectivityHistory),
-		})
+		groups := timeline.GroupConnectivity(node.ConnectivityHistory, node.Probes)
+		if len(groups) == 0 {
+			items = append(items, overviewItem{
+				ID:       buildConnectivityID(nodeID, ""),
+				Name:     "Connectivity",
+				Kind:     overviewConnectivityKey,
+				NodeID:   nodeID,
+				NodeName: fallbackName(node.Node),
+				Buckets:  newOverviewBuckets(buckets),
+			})
+			continue
+		}
+		for _, group := range groups {
+			items = append(items, overviewItem{
+				ID:       buildConnectivityID(nodeID, group.Probe.ID),
+				Name:     group.Probe.Name,
+				Kind:     overviewConnectivityKey,
+				NodeID:   nodeID,
+				NodeName: fallbackName(node.Node),
+				Buckets:  buildConnectivityBuckets(buckets, group.Samples),
+			})
+		}
 	}
 	if len(items) == 0 {
 		items = append(items, overviewItem{
-			ID:       buildConnectivityID(s.node.ID),
+			ID:       buildConnectivityID(s.node.ID, ""),
 			Name:     "Connectivity",
 			Kind:     overviewConnectivityKey,
 			NodeID:   s.node.ID,
@@ -337,7 +353,11 @@ func mapTimelineToBuckets(points []models.TimelinePoint, buckets []timeBucket) [
 					detail = timelineDetail(point)
 					break
 				}
-				if pointState == overviewStateOK && state != overviewStateOK {
+				if pointState == overviewStateBlocked && state != overviewStateBlocked {
+					state = overviewStateBlocked
+					detail = timelineDetail(point)
+				}
+				if pointState == overviewStateOK && state == overviewStateUnknown {
 					state = overviewStateOK
 					detail = timelineDetail(point)
 				}
@@ -376,6 +396,8 @@ func timelineState(className string) string {
 		return overviewStateOK
 	case "state-error", "state-warning":
 		return overviewStateIssue
+	case "state-blocked":
+		return overviewStateBlocked
 	default:
 		return overviewStateUnknown
 	}
@@ -429,12 +451,15 @@ func (s *Server) isLocalPeer(peer cluster.PeerSnapshot) bool {
 	return false
 }
 
-func buildConnectivityID(nodeID string) string {
-	id := strings.TrimSpace(nodeID)
-	if id == "" {
-		return overviewConnectivityID
+func buildConnectivityID(nodeID, probeID string) string {
+	id := overviewConnectivityID
+	if probeID != "" {
+		id = fmt.Sprintf("%s::%s", overviewConnectivityID, probeID)
+	}
+	if node := strings.TrimSpace(nodeID); node != "" {
+		return fmt.Sprintf("%s::%s", node, id)
 	}
-	return fmt.Sprintf("%s::%s", id, overviewConnectivityID)
+	return id
 }
 
 func overviewRangeKey(start, end time.Time) string {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"strconv"
@@ -33,6 +35,7 @@ type Server struct {
 	connectivity   monitor.ConnectivitySource
 	targets        []models.Target
 	clusterService *cluster.Service
+	pings          PingRecorder
 	historyLimit   int
 	cacheMu        sync.RWMutex
 	timelineCache  map[string]timelineCacheEntry
@@ -45,6 +48,9 @@ type timelineCacheEntry struct {
 
 const connectivityHistoryCap = 5000
 
+// maxPingBodyBytes caps how much of a ping request body is read.
+const maxPingBodyBytes = 10 << 10
+
 // New creates a configured HTTP server for the monitor.
 func New(
 	addr string,
@@ -82,13 +88,23 @@ func New(
 		timelineCache:  make(map[string]timelineCacheEntry),
 	}
 	s.node.IntervalMinutes = int(interval / time.Minute)
-	if node.ConnectivityIntervalSeconds > 0 {
-		s.node.ConnectivityIntervalSeconds = node.ConnectivityIntervalSeconds
+	if connectivity != nil {
+		s.node.ConnectivityIntervalSeconds = cluster.LegacyConnectivityInterval(connectivity.Probes())
 	}
 	s.registerRoutes(mux)
 	return s
 }
 
+// PingRecorder accepts pings sent by jobs to heartbeat targets.
+type PingRecorder interface {
+	RecordPing(id string, ping monitor.Ping) error
+}
+
+// SetPingRecorder enables POST /api/ping/{target_id} for heartbeat targets.
+func (s *Server) SetPingRecorder(recorder PingRecorder) {
+	s.pings = recorder
+}
+
 // Run blocks and serves HTTP traffic.
 func (s *Server) Run() error {
 	return s.httpServer.ListenAndServe()
@@ -135,6 +151,7 @@ func (s *Server) registerRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/cluster", s.handleCluster)
 	mux.HandleFunc("/api/overview", s.handleOverview)
 	mux.HandleFunc("/ws/overview", s.handleOverviewWS)
+	mux.HandleFunc("/api/ping/", s.handlePing)
 }
 
 func (s *Server) handleLatest(w http.ResponseWriter, _ *http.Request) {
@@ -149,6 +166,63 @@ func (s *Server) handleLatest(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, entry)
 }
 
+// handlePing accepts /api/ping/{id}, /api/ping/{id}/start, /api/ping/{id}/fail and
+// /api/ping/{id}/{exit_code}. The request body is kept as the check output.
+func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	if s.pings == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "heartbeats are not enabled"})
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/ping/"), "/"), "/")
+	if len(parts) > 2 || parts[0] == "" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown ping path"})
+		return
+	}
+	ping := monitor.Ping{Kind: monitor.PingSuccess}
+	if len(parts) == 2 {
+		switch parts[1] {
+		case monitor.PingStart, monitor.PingFail:
+			ping.Kind = parts[1]
+		default:
+			code, err := strconv.Atoi(parts[1])
+			if err != nil || code < 0 || code > 255 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "expected start, fail or an exit code"})
+				return
+			}
+			ping.ExitCode = &code
+		}
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPingBodyBytes))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "read body failed"})
+		return
+	}
+	ping.Output = string(body)
+	ping.Token = r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		ping.Token = strings.TrimPrefix(auth, "Bearer ")
+	}
+
+	if err := s.pings.RecordPing(parts[0], ping); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, monitor.ErrUnknownHeartbeat):
+			status = http.StatusNotFound
+		case errors.Is(err, monitor.ErrPingUnauthorized):
+			status = http.StatusUnauthorized
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
 func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
 	window := parseWindow(r)
 	history := s.storage.HistorySince(window.start)
@@ -169,11 +243,14 @@ func (s *Server) handleUptime(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleNodeStatus(w http.ResponseWriter, _ *http.Request) {
 	entry, ok := s.storage.Latest()
+	connectivity := s.latestConnectivity()
 	resp := cluster.NodeStatusResponse{
-		Node:         s.node,
-		GeneratedAt:  time.Now().UTC(),
-		Connectivity: s.latestConnectivity(),
-		Targets:      s.targets,
+		Node:               s.node,
+		GeneratedAt:        time.Now().UTC(),
+		ConnectivityProbes: connectivity,
+		Probes:             s.connectivityProbes(),
+		Targets:            s.targets,
+		Connectivity:       cluster.LegacyConnectivity(connectivity),
 	}
 	resp.Node.IntervalMinutes = int(s.interval / time.Minute)
 	if ok {
@@ -184,27 +261,37 @@ func (s *Server) handleNodeStatus(w http.ResponseWriter, _ *http.Request) {
 
 func (s *Server) handleNodeHistory(w http.ResponseWriter, r *http.Request) {
 	window := parseWindow(r)
+	version := s.storage.Version()
 	history := s.storage.HistorySince(window.start)
 	history = filterHistory(history, window.start, window.end)
+	var status *models.StatusEntry
+	if latest, ok := s.storage.Latest(); ok {
+		status = &latest
+	}
+	serviceTimelines := s.cachedServiceTimelines(window, history, status, version)
 	if limit := parseLimit(r, s.historyLimit); limit > 0 && len(history) > limit {
 		history = history[len(history)-limit:]
 	}
 	connectivityFull := s.connectivityHistory(window.start, window.end)
-	connectivityTimeline := timeline.BuildConnectivityTimeline(connectivityFull, window.start, window.end, timeline.DefaultTimelinePoints)
+	probes := s.connectivityProbes()
+	connectivityTimelines := timeline.BuildConnectivityTimelines(connectivityFull, probes, window.start, window.end, timeline.DefaultTimelinePoints)
 	connectivity := connectivityFull
 	if limit := parseLimit(r, connectivityHistoryCap); limit > 0 && len(connectivity) > limit {
 		connectivity = connectivity[len(connectivity)-limit:]
 	}
 	resp := cluster.NodeHistoryResponse{
-		Node:                 s.node,
-		History:              history,
-		Connectivity:         connectivity,
-		ConnectivityTimeline: connectivityTimeline,
-		GeneratedAt:          time.Now().UTC(),
-		Range:                window.key,
-		RangeStart:           window.start,
-		RangeEnd:             window.end,
-		Targets:              s.targets,
+		Node:                  s.node,
+		History:               history,
+		ServiceTimelines:      serviceTimelines,
+		Connectivity:          connectivity,
+		ConnectivityTimelines: connectivityTimelines,
+		Probes:                probes,
+		GeneratedAt:           time.Now().UTC(),
+		Range:                 window.key,
+		RangeStart:            window.start,
+		RangeEnd:              window.end,
+		Targets:               s.targets,
+		ConnectivityTimeline:  cluster.LegacyConnectivityTimeline(connectivityTimelines),
 	}
 	resp.Node.IntervalMinutes = int(s.interval / time.Minute)
 	writeJSON(w, http.StatusOK, resp)
@@ -253,24 +340,25 @@ func (s *Server) localPeerSnapshot(win window) cluster.PeerSnapshot {
 	}
 	services := metrics.ComputeServiceUptime(history, win.start, win.end, s.interval, s.targets)
 	timelines := s.cachedServiceTimelines(win, history, status, version)
-	var connectivity *models.ConnectivityStatus
-	if sample := s.latestConnectivity(); sample != nil {
-		connectivity = sample
-	}
+	probes := s.connectivityProbes()
 	connectivityHistory := s.connectivityHistory(win.start, win.end)
-	connectivityTimeline := timeline.BuildConnectivityTimeline(connectivityHistory, win.start, win.end, timeline.DefaultTimelinePoints)
+	connectivityTimelines := timeline.BuildConnectivityTimelines(connectivityHistory, probes, win.start, win.end, timeline.DefaultTimelinePoints)
+	connectivity := s.latestConnectivity()
 	return cluster.PeerSnapshot{
-		Node:                 s.node,
-		Status:               status,
-		Connectivity:         connectivity,
-		ConnectivityHistory:  connectivityHistory,
-		ConnectivityTimeline: connectivityTimeline,
-		History:              nil,
-		ServiceTimelines:     timelines,
-		Services:             services,
-		Targets:              s.targets,
-		UpdatedAt:            time.Now().UTC(),
-		Source:               "local",
+		Node:                  s.node,
+		Status:                status,
+		ConnectivityProbes:    connectivity,
+		ConnectivityHistory:   connectivityHistory,
+		ConnectivityTimelines: connectivityTimelines,
+		Probes:                probes,
+		History:               nil,
+		ServiceTimelines:      timelines,
+		Services:              services,
+		Targets:               s.targets,
+		UpdatedAt:             time.Now().UTC(),
+		Source:                "local",
+		Connectivity:          cluster.LegacyConnectivity(connectivity),
+		ConnectivityTimeline:  cluster.LegacyConnectivityTimeline(connectivityTimelines),
 	}
 }
 
@@ -376,16 +464,18 @@ func writeJSON(w http.ResponseWriter, status int, payload any) {
 	_ = enc.Encode(payload)
 }
 
-func (s *Server) latestConnectivity() *models.ConnectivityStatus {
+func (s *Server) latestConnectivity() []models.ConnectivityStatus {
 	if s.connectivity == nil {
 		return nil
 	}
-	sample, ok := s.connectivity.Latest()
-	if !ok {
+	return s.connectivity.Latest()
+}
+
+func (s *Server) connectivityProbes() []models.ConnectivityProbe {
+	if s.connectivity == nil {
 		return nil
 	}
-	clone := sample
-	return &clone
+	return s.connectivity.Probes()
 }
 
 func (s *Server) connectivityHistory(start, end time.Time) []models.ConnectivityStatus {
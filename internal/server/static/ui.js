@@ -25,6 +25,19 @@ const RANGE_LABELS = {
   "30d": "Last 30 days",
 };
 const OVERVIEW_LIMIT = 9;
+const DEFAULT_PROBE_ID = "dns";
+const WARNING_STATES = [
+  "activating",
+  "deactivating",
+  "reloading",
+  "maintenance",
+  "warning",
+  "restarted",
+  "unconfirmed",
+  "recovering",
+  "pending",
+  "starting",
+];
 const OVERVIEW_BUCKET_COUNT = 3;
 const DEBUG_VERSION = "20251108";
 const SVG_NS = "http://www.w3.org/2000/svg";
@@ -190,8 +203,8 @@ function renderNodeCard(node, rangeStart, rangeEnd) {
   head.className = "card-head";
   head.innerHTML = `
     <div class="card-title">
-      <span class="service-name">${nodeName}</span>
-      <span class="meta-text">ID: ${nodeId} - ${sourceLabel}</span>
+      <span class="service-name">${escapeHTML(nodeName)}</span>
+      <span class="meta-text">ID: ${escapeHTML(nodeId)} - ${sourceLabel}</span>
     </div>
     <span class="uptime-pill ${uptimeClass}">${uptimeLabel}</span>
   `;
@@ -199,26 +212,28 @@ function renderNodeCard(node, rangeStart, rangeEnd) {
 
   const meta = document.createElement("div");
   meta.className = "card-meta";
-  meta.appendChild(createMetaBadge("Services", `<span>${services.length}</span>`));
+  meta.appendChild(createMetaBadge("Usługi", `<span>${services.length}</span>`));
   if (updatedAt) {
     meta.appendChild(
-      createMetaBadge("Last sync", `<span>${formatTimestamp(updatedAt)}</span>`),
+      createMetaBadge("Ostatnia synchronizacja", `<span>${formatTimestamp(updatedAt)}</span>`),
     );
   }
   if (node.status?.timestamp) {
     meta.appendChild(
       createMetaBadge(
-        "Latest sample",
+        "Ostatnia próbka",
         `<span>${formatTimestamp(new Date(node.status.timestamp))}</span>`,
       ),
     );
   }
   card.appendChild(meta);
 
-  const connectivityData = buildConnectivityData(node, rangeStart, rangeEnd);
-  if (connectivityData) {
-    card.appendChild(renderConnectivitySection(connectivityData));
-  }
+  buildConnectivityProbes(node).forEach((probe) => {
+    const connectivityData = buildConnectivityData(node, probe, rangeStart, rangeEnd);
+    if (connectivityData) {
+      card.appendChild(renderConnectivitySection(connectivityData));
+    }
+  });
 
   const list = document.createElement("div");
   list.className = "service-list";
@@ -274,6 +289,12 @@ function renderServiceRow(service) {
   metaText.className = "meta-text";
   metaText.textContent = `ID: ${service.id}`;
   title.appendChild(metaText);
+  if (service.latestCheck?.ok && service.latestCheck.output) {
+    const outputText = document.createElement("span");
+    outputText.className = "meta-text";
+    outputText.textContent = service.latestCheck.output;
+    title.appendChild(outputText);
+  }
   head.appendChild(title);
 
   const meta = document.createElement("div");
@@ -281,14 +302,72 @@ function renderServiceRow(service) {
 
   const stateChip = resolveStateChip(service.latestCheck);
   meta.appendChild(
-    createMetaBadge("Stan", `<span class="state-chip ${stateChip.className}">${stateChip.label}</span>`),
+    createMetaBadge("Stan", `<span class="state-chip ${stateChip.className}">${escapeHTML(stateChip.label)}</span>`),
   );
+  const unit = service.latestCheck?.unit;
+  if (unit) {
+    if (unit.result === "oom-kill") {
+      meta.appendChild(createMetaBadge("Wynik", '<span class="state-chip error">OOM-killed</span>'));
+    } else if (unit.result && unit.result !== "success") {
+      meta.appendChild(createMetaBadge("Wynik", `<span class="state-chip error">${escapeHTML(unit.result)}</span>`));
+    }
+    if (unit.n_restarts > 0) {
+      meta.appendChild(createMetaBadge("Restarty", `<span>${unit.n_restarts}</span>`));
+    }
+    if (unit.memory_bytes > 0) {
+      meta.appendChild(createMetaBadge("Pamięć", `<span>${(unit.memory_bytes / 1048576).toFixed(1)} MiB</span>`));
+    }
+    const isJob = service.kind === "oneshot" || service.kind === "timer";
+    if (!isJob && unit.active_since && (service.latestCheck.ok || unit.active_state === "active")) {
+      meta.appendChild(createMetaBadge("Od", `<span>${new Date(unit.active_since).toLocaleString()}</span>`));
+    }
+    if (isJob && unit.last_run_end) {
+      meta.appendChild(createMetaBadge("Ostatnie uruchomienie", `<span>${new Date(unit.last_run_end).toLocaleString()}</span>`));
+    }
+    if (unit.next_elapse) {
+      meta.appendChild(createMetaBadge("Następne uruchomienie", `<span>${new Date(unit.next_elapse).toLocaleString()}</span>`));
+    }
+  }
+  const tlsInfo = service.latestCheck?.tls;
+  if (tlsInfo && Number.isFinite(tlsInfo.days_left)) {
+    const certClass = !tlsInfo.chain_valid || tlsInfo.days_left < 0 ? "error" : service.latestCheck.state === "warning" ? "warning" : "";
+    meta.appendChild(
+      createMetaBadge("Certyfikat", `<span class="state-chip ${certClass}">${tlsInfo.days_left} d</span>`),
+    );
+  }
+  const container = service.latestCheck?.container;
+  if (container?.health) {
+    const healthClass = container.health === "unhealthy" ? "error" : container.health === "starting" ? "warning" : "";
+    meta.appendChild(createMetaBadge("Kondycja", `<span class="state-chip ${healthClass}">${escapeHTML(container.health)}</span>`));
+  }
+  if (container?.restart_count > 0) {
+    meta.appendChild(createMetaBadge("Restarty", `<span>${container.restart_count}</span>`));
+  }
+  if (container && container.status !== "running" && container.exit_code !== 0) {
+    meta.appendChild(createMetaBadge("Kod wyjścia", `<span class="state-chip error">${container.exit_code}</span>`));
+  }
+  if (container?.status === "running" && container.started_at) {
+    meta.appendChild(createMetaBadge("Od", `<span>${new Date(container.started_at).toLocaleString()}</span>`));
+  }
+  const heartbeat = service.latestCheck?.heartbeat;
+  if (heartbeat?.last_ping) {
+    meta.appendChild(createMetaBadge("Ostatni ping", `<span>${new Date(heartbeat.last_ping).toLocaleString()}</span>`));
+  }
+  if (heartbeat && Number.isFinite(heartbeat.exit_code) && heartbeat.exit_code !== 0) {
+    meta.appendChild(createMetaBadge("Kod wyjścia", `<span class="state-chip error">${heartbeat.exit_code}</span>`));
+  }
+  if (service.latestCheck?.status_code) {
+    meta.appendChild(createMetaBadge("Kod HTTP", `<span>${service.latestCheck.status_code}</span>`));
+  }
+  if (Number.isFinite(service.latestCheck?.latency_ms)) {
+    meta.appendChild(createMetaBadge("Opóźnienie", `<span>${service.latestCheck.latency_ms} ms</span>`));
+  }
 
   if (service.metric && Number.isFinite(service.metric.uptime_percent)) {
     const className = uptimeLevel(service.metric.uptime_percent);
     meta.appendChild(
       createMetaBadge(
-        "Uptime",
+        "Dostępność",
         `<span class="uptime-stat ${className}">${service.metric.uptime_percent.toFixed(
           2,
         )}%</span>`,
@@ -296,15 +375,15 @@ function renderServiceRow(service) {
     );
     meta.appendChild(
       createMetaBadge(
-        "Checks",
+        "Sprawdzenia",
         `<span>${service.metric.total_checks} (${service.metric.passing}/${service.metric.failing})</span>`,
       ),
     );
     if (service.metric.missing) {
-      meta.appendChild(createMetaBadge("Missing", `<span>${service.metric.missing}</span>`));
+      meta.appendChild(createMetaBadge("Brakujące", `<span>${service.metric.missing}</span>`));
     }
   } else {
-    meta.appendChild(createMetaBadge("Uptime", "<span>-</span>"));
+    meta.appendChild(createMetaBadge("Dostępność", "<span>-</span>"));
   }
 
   head.appendChild(meta);
@@ -327,6 +406,18 @@ function renderServiceRow(service) {
     });
   }
   row.appendChild(timeline);
+  (service.metrics || []).forEach((series) => {
+    const chart = renderMetricChart(series);
+    if (chart) {
+      row.appendChild(chart);
+    }
+  });
+  if (service.metricsOmitted > 0) {
+    const omitted = document.createElement("span");
+    omitted.className = "meta-text";
+    omitted.textContent = `+${service.metricsOmitted} more metric(s) not charted`;
+    row.appendChild(omitted);
+  }
 
   if (service.latestCheck?.error) {
     const errorBox = document.createElement("div");
@@ -338,6 +429,82 @@ function renderServiceRow(service) {
   return row;
 }
 
+function renderMetricChart(series) {
+  const points = (series.points || []).slice(-HISTORY_POINTS);
+  const values = points.filter(Boolean);
+  if (!values.length) {
+    return null;
+  }
+  const low = Math.min(...values.map((point) => point.min));
+  const high = Math.max(...values.map((point) => point.max));
+  const span = high - low || 1;
+  const width = points.length * 10;
+  const height = 32;
+  const x = (index) => index * 10 + 5;
+  const y = (value) => height - 2 - ((value - low) / span) * (height - 4);
+
+  const svgNS = "http://www.w3.org/2000/svg";
+  const svg = document.createElementNS(svgNS, "svg");
+  svg.setAttribute("class", "metric-chart-plot");
+  svg.setAttribute("viewBox", `0 0 ${width} ${height}`);
+  svg.setAttribute("preserveAspectRatio", "none");
+  const unit = series.unit ? ` ${series.unit}` : "";
+  points.forEach((point, index) => {
+    if (!point) {
+      return;
+    }
+    const band = document.createElementNS(svgNS, "rect");
+    band.setAttribute("class", "metric-band");
+    band.setAttribute("x", index * 10 + 1);
+    band.setAttribute("width", 8);
+    band.setAttribute("y", y(point.max));
+    band.setAttribute("height", Math.max(y(point.min) - y(point.max), 1));
+    const title = document.createElementNS(svgNS, "title");
+    title.textContent = `min ${formatMetricValue(point.min)} / avg ${formatMetricValue(point.avg)} / max ${formatMetricValue(
+      point.max,
+    )} / p95 ${formatMetricValue(point.p95)}${unit} (${point.count} sample(s))`;
+    band.appendChild(title);
+    svg.appendChild(band);
+  });
+  const line = document.createElementNS(svgNS, "polyline");
+  line.setAttribute("class", "metric-line");
+  line.setAttribute(
+    "points",
+    points
+      .map((point, index) => (point ? `${x(index)},${y(point.avg)}` : null))
+      .filter(Boolean)
+      .join(" "),
+  );
+  svg.appendChild(line);
+
+  const chart = document.createElement("div");
+  chart.className = "metric-chart";
+  const label = document.createElement("span");
+  label.className = "meta-text";
+  const last = values[values.length - 1];
+  label.textContent = `${series.name}: ${formatMetricValue(last.avg)}${unit} (range ${formatMetricValue(low)}-${formatMetricValue(high)})`;
+  chart.appendChild(label);
+  chart.appendChild(svg);
+  return chart;
+}
+
+function formatMetricValue(value) {
+  if (!Number.isFinite(value)) {
+    return "-";
+  }
+  const abs = Math.abs(value);
+  if (abs >= 1e9) {
+    return `${(value / 1e9).toFixed(1)}G`;
+  }
+  if (abs >= 1e6) {
+    return `${(value / 1e6).toFixed(1)}M`;
+  }
+  if (abs >= 1e4) {
+    return `${(value / 1e3).toFixed(1)}k`;
+  }
+  return Number.isInteger(value) ? String(value) : value.toFixed(2);
+}
+
 function createExternalLinkIcon() {
   const svg = document.createElementNS(SVG_NS, "svg");
   svg.setAttribute("viewBox", "0 0 20 20");
@@ -368,7 +535,10 @@ function renderConnectivitySection(data) {
   left.className = "connectivity-head-left";
   const title = document.createElement("span");
   title.className = "section-title";
-  title.textContent = `Connectivity (${data.rangeLabel})`;
+  title.textContent = `${data.name} (${data.rangeLabel})`;
+  if (data.target) {
+    title.title = data.target;
+  }
   left.appendChild(title);
 
   const summary = document.createElement("span");
@@ -414,13 +584,41 @@ function renderConnectivitySection(data) {
   return section;
 }
 
-function buildConnectivityData(node, rangeStart, rangeEnd) {
+function buildConnectivityProbes(node) {
+  const probes = Array.isArray(node.probes) ? node.probes.slice() : [];
+  const known = new Set(probes.map((probe) => probe.id));
+  const samples = [
+    ...(Array.isArray(node.connectivity_probes) ? node.connectivity_probes : []),
+    ...(Array.isArray(node.connectivity_history) ? node.connectivity_history : []),
+  ];
+  samples.forEach((sample) => {
+    const id = connectivityProbeId(sample);
+    if (!known.has(id)) {
+      known.add(id);
+      probes.push({ id, name: id, target: sample?.target });
+    }
+  });
+  return probes;
+}
+
+function connectivityProbeId(sample) {
+  return sample?.probe || DEFAULT_PROBE_ID;
+}
+
+function buildConnectivityData(node, probe, rangeStart, rangeEnd) {
+  const probeId = probe.id;
+  const probeName = probe.name || probe.id;
   const historyRaw = Array.isArray(node.connectivity_history)
-    ? node.connectivity_history
+    ? node.connectivity_history.filter((entry) => connectivityProbeId(entry) === probeId)
     : [];
-  const latest = node.connectivity || null;
+  const latest =
+    (Array.isArray(node.connectivity_probes) ? node.connectivity_probes : []).find(
+      (entry) => connectivityProbeId(entry) === probeId,
+    ) || null;
   const timelinePointsRaw =
-    node.connectivity_timeline || node.connectivityTimeline || null;
+    (Array.isArray(node.connectivity_timelines) ? node.connectivity_timelines : []).find(
+      (entry) => entry?.probe_id === probeId,
+    )?.timeline || null;
   if (!historyRaw.length && !latest) {
     return null;
   }
@@ -438,8 +636,8 @@ function buildConnectivityData(node, rangeStart, rangeEnd) {
       const ok = Boolean(entry.ok);
       const state = ok ? "online" : entry.error ? "offline" : "unknown";
       return {
-        id: "connectivity",
-        name: "Connectivity",
+        id: probeId,
+        name: probeName,
         ok,
         state,
         error: entry.error,
@@ -455,8 +653,8 @@ function buildConnectivityData(node, rangeStart, rangeEnd) {
       const exists = history.some((item) => item.timestamp === isoLatest);
       if (!exists) {
         history.push({
-          id: "connectivity",
-          name: "Connectivity",
+          id: probeId,
+          name: probeName,
           ok: Boolean(latest.ok),
           state: latest.ok ? "online" : latest.error ? "offline" : "unknown",
           error: latest.error,
@@ -488,14 +686,14 @@ function buildConnectivityData(node, rangeStart, rangeEnd) {
   let filledHistory = history;
   let timeline = mapTimelinePoints(timelinePointsRaw);
   if (!timeline.length) {
-    const intervalMs = getConnectivityIntervalMs(node, history);
+    const intervalMs = getConnectivityIntervalMs(probe, history);
     filledHistory = fillMissingHistory(
       history,
       intervalMs,
       windowStart,
       windowEnd,
-      "connectivity",
-      "Connectivity",
+      probeId,
+      probeName,
     );
     timeline = buildTimelineSegments(
       filledHistory,
@@ -528,6 +726,8 @@ function buildConnectivityData(node, rangeStart, rangeEnd) {
       : RANGE_LABELS[currentRange] || "Selected range";
 
   return {
+    name: probeName,
+    target: probe.target || latest?.target || "",
     timeline,
     summary,
     stats,
@@ -584,10 +784,10 @@ function formatConnectivitySummary(stats) {
   return parts.join(" · ");
 }
 
-function getConnectivityIntervalMs(node, history) {
+function getConnectivityIntervalMs(probe, history) {
   let intervalMs = estimateIntervalFromHistory(history);
   if (!Number.isFinite(intervalMs) || intervalMs <= 0) {
-    const configured = Number(node?.node?.connectivity_interval_seconds);
+    const configured = Number(probe?.interval_seconds);
     if (Number.isFinite(configured) && configured > 0) {
       intervalMs = configured * 1000;
     }
@@ -636,8 +836,9 @@ function buildServiceData(node, rangeStart, rangeEnd) {
         id: check.id,
         name: check.name || check.id,
         ok: Boolean(check.ok),
-        state: check.state || (check.ok ? "active" : "unknown"),
+        state: check.state || (check.ok ? "active" : ""),
         error: check.error,
+        excluded: check.state === "blocked" && Boolean(targetMap.get(check.id)?.exclude_blocked),
         timestamp,
       });
       historyMap.set(check.id, list);
@@ -691,11 +892,14 @@ function buildServiceData(node, rangeStart, rangeEnd) {
     services.push({
       id,
       name,
+      kind: target?.kind || "",
       url: serviceUrl,
       metric,
       latestCheck,
       history,
       timeline,
+      metrics: compact?.metrics || [],
+      metricsOmitted: compact?.metricsOmitted || 0,
     });
   });
 
@@ -754,6 +958,8 @@ function buildCompactTimelineMap(raw) {
     map.set(item.service_id, {
       name: item.service_name || item.serviceId || item.service_id,
       segments,
+      metrics: Array.isArray(item.metrics) ? item.metrics : [],
+      metricsOmitted: Number(item.metrics_omitted) || 0,
     });
   });
   return map;
@@ -793,12 +999,31 @@ function formatCompactTimelineTooltip(point) {
         const tsLabel = ts ? formatTimestamp(ts) : "Unknown time";
         const state = detail.state || "no state";
         const error = detail.error ? ` - ${detail.error}` : "";
-        return `${tsLabel}: ${state}${error}`;
+        let duration =
+          detail.duration_seconds > 0 ? ` (for ${formatDurationSeconds(detail.duration_seconds)})` : "";
+        if (Number.isFinite(detail.exit_status)) {
+          duration = ` (took ${formatDurationSeconds(detail.duration_seconds || 0)}, exit ${detail.exit_status})`;
+        }
+        const messages = Array.isArray(detail.messages)
+          ? detail.messages.map((message) => `\n  ${message}`).join("")
+          : "";
+        const remediation = detail.remediation ? `\n  remediation: ${detail.remediation}` : "";
+        return `${tsLabel}: ${state}${duration}${error}${messages}${remediation}`;
       })
     : [];
   return details.length ? `${base}\n${details.join("\n")}` : base;
 }
 
+function formatDurationSeconds(seconds) {
+  if (seconds < 60) {
+    return `${Math.round(seconds)}s`;
+  }
+  if (seconds < 3600) {
+    return `${Math.floor(seconds / 60)}m ${Math.round(seconds % 60)}s`;
+  }
+  return `${Math.floor(seconds / 3600)}h ${Math.round((seconds % 3600) / 60)}m`;
+}
+
 function getIntervalMs(node, history) {
   const minutes = Number(node?.node?.interval_minutes);
   if (Number.isFinite(minutes) && minutes > 0) {
@@ -992,6 +1217,8 @@ function determineBucketState(entries) {
   let hasWarning = false;
   let hasSuccess = false;
   let hasMissing = false;
+  let hasBlocked = false;
+  let hasExcluded = false;
 
   entries.forEach((entry) => {
     const state = (entry.state || "").toLowerCase();
@@ -1006,6 +1233,18 @@ function determineBucketState(entries) {
       hasError = true;
       return;
     }
+    if (state === "blocked") {
+      if (entry.excluded) {
+        hasExcluded = true;
+      } else {
+        hasBlocked = true;
+      }
+      return;
+    }
+    if (entry.ok && WARNING_STATES.includes(state)) {
+      hasWarning = true;
+      return;
+    }
     if (entry.ok || state === "active" || state === "running") {
       hasSuccess = true;
       return;
@@ -1014,11 +1253,11 @@ function determineBucketState(entries) {
       hasMissing = true;
       return;
     }
-    if (["activating", "deactivating", "reloading", "maintenance"].includes(state)) {
+    if (WARNING_STATES.includes(state)) {
       hasWarning = true;
       return;
     }
-    if (!state || state === "unknown") {
+    if (!state || (state === "unknown" && !entry.error)) {
       hasMissing = true;
       return;
     }
@@ -1028,6 +1267,9 @@ function determineBucketState(entries) {
   if (hasError) {
     return { className: "error", label: "Unavailable" };
   }
+  if (hasBlocked) {
+    return { className: "blocked", label: "Dependency down" };
+  }
   if (hasMissing) {
     return { className: "missing", label: "No data" };
   }
@@ -1037,6 +1279,9 @@ function determineBucketState(entries) {
   if (hasSuccess) {
     return { className: "success", label: "Operational" };
   }
+  if (hasExcluded) {
+    return { className: "blocked", label: "Dependency down" };
+  }
   return { className: "missing", label: "No data" };
 }
 
@@ -1073,20 +1318,48 @@ function renderIncidents(nodes) {
         details: node.error,
       });
     }
-    if (node.connectivity && !node.connectivity.ok) {
-      incidents.push({
-        title: `${nodeName} - connectivity`,
-        details:
-          node.connectivity.error ||
-          `No response from ${node.connectivity.target || "probe target"}`,
+    const probeNames = new Map(
+      (Array.isArray(node.probes) ? node.probes : []).map((probe) => [probe.id, probe.name]),
+    );
+    // Root causes by target or probe ID; blocked checks are listed under them.
+    const roots = new Map();
+    (Array.isArray(node.connectivity_probes) ? node.connectivity_probes : [])
+      .filter((sample) => !sample.ok)
+      .forEach((sample) => {
+        const probeId = connectivityProbeId(sample);
+        const item = {
+          title: `${nodeName} - ${probeNames.get(probeId) || probeId}`,
+          details: sample.error || `No response from ${sample.target || "probe target"}`,
+          blocked: [],
+        };
+        roots.set(probeId, item);
+        incidents.push(item);
       });
-    }
-    (node.status?.checks || [])
-      .filter((check) => !check.ok)
+    const failing = (node.status?.checks || []).filter((check) => !check.ok);
+    failing
+      .filter((check) => !check.blocked_by)
+      .forEach((check) => {
+        const item = {
+          title: `${nodeName} / ${check.name || check.id}`,
+          details: `${check.state || "no state"} - ${check.error || "no details"}`,
+          lines: [...journalMessages(check.journal), ...remediationLines(check.remediation)],
+          blocked: [],
+        };
+        roots.set(check.id, item);
+        incidents.push(item);
+      });
+    failing
+      .filter((check) => check.blocked_by)
       .forEach((check) => {
+        const root = roots.get(check.blocked_by);
+        if (root) {
+          root.blocked.push(check);
+          return;
+        }
         incidents.push({
           title: `${nodeName} / ${check.name || check.id}`,
           details: `${check.state || "no state"} - ${check.error || "no details"}`,
+          blocked: [],
         });
       });
   });
@@ -1104,10 +1377,51 @@ function renderIncidents(nodes) {
   incidents.forEach((item) => {
     const el = document.createElement("li");
     el.className = "incident-item";
-    el.innerHTML = `<strong>${item.title}</strong><span>${item.details}</span>`;
+    // Titles and details carry check output, which must never be parsed as markup.
+    const title = document.createElement("strong");
+    title.textContent = item.title;
+    const details = document.createElement("span");
+    details.textContent = item.details;
+    el.append(title, details);
+    (item.lines || []).forEach((line) => {
+      const code = document.createElement("code");
+      code.className = "incident-line";
+      code.textContent = line;
+      el.appendChild(code);
+    });
+    if (item.blocked?.length) {
+      const children = document.createElement("ul");
+      children.className = "incident-blocked";
+      item.blocked.forEach((check) => {
+        const child = document.createElement("li");
+        child.textContent = `${check.name || check.id} - blocked`;
+        child.title = check.error || "";
+        children.appendChild(child);
+      });
+      el.appendChild(children);
+    }
     incidentList.appendChild(el);
   });
-  incidentMeta.textContent = `${incidents.length} item(s) require attention`;
+  const blockedCount = incidents.reduce((sum, item) => sum + (item.blocked?.length || 0), 0);
+  incidentMeta.textContent = blockedCount
+    ? `${incidents.length} item(s) require attention, ${blockedCount} blocked service(s) grouped under their cause`
+    : `${incidents.length} item(s) require attention`;
+}
+
+function remediationLines(remediation) {
+  if (!remediation) {
+    return [];
+  }
+  const outcome = remediation.ok ? "succeeded" : `failed: ${remediation.error || "no details"}`;
+  return [`${remediation.command} - ${outcome} (attempt ${remediation.attempt})`];
+}
+
+function journalMessages(journal) {
+  const lines = Array.isArray(journal?.lines) ? journal.lines : [];
+  return lines
+    .slice(-3)
+    .reverse()
+    .map((line) => line.message || "");
 }
 
 function getNodeName(node) {
@@ -1128,13 +1442,19 @@ function resolveStateChip(latestCheck) {
     return { label: "no data", className: "unknown" };
   }
   const state = (latestCheck.state || "").toLowerCase();
+  if (latestCheck.ok && WARNING_STATES.includes(state)) {
+    return { label: state, className: "warning" };
+  }
   if (latestCheck.ok || state === "active" || state === "running") {
     return { label: state || "active", className: "" };
   }
   if (state === "missing") {
     return { label: "missing data", className: "warning" };
   }
-  if (["activating", "deactivating", "reloading"].includes(state)) {
+  if (state === "blocked") {
+    return { label: "blocked", className: "blocked" };
+  }
+  if (WARNING_STATES.includes(state)) {
     return { label: state, className: "warning" };
   }
   if (!state) {
@@ -1145,21 +1465,34 @@ function resolveStateChip(latestCheck) {
 
 function stateToClass(state, ok) {
   const normalized = (state || "").toLowerCase();
+  if (ok && WARNING_STATES.includes(normalized)) {
+    return "state-warning";
+  }
   if (ok || normalized === "active" || normalized === "running") {
     return "state-success";
   }
   if (normalized === "missing") {
     return "state-missing";
   }
-  if (["activating", "deactivating", "reloading", "maintenance"].includes(normalized)) {
+  if (normalized === "blocked") {
+    return "state-blocked";
+  }
+  if (WARNING_STATES.includes(normalized)) {
     return "state-warning";
   }
-  if (!normalized || normalized === "unknown") {
+  if (!normalized) {
     return "state-unknown";
   }
   return "state-error";
 }
 
+function escapeHTML(value) {
+  return String(value ?? "").replace(
+    /[&<>"']/g,
+    (ch) => ({ "&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;" })[ch],
+  );
+}
+
 function createMetaBadge(label, valueHTML) {
   const wrapper = document.createElement("span");
   wrapper.className = "meta-badge";
@@ -1293,9 +1626,9 @@ async function fetchJSON(url) {
 }
 
 function showErrorState(err) {
-  statusCards.innerHTML = `<div class="empty-state">Error fetching data: ${
-    err?.message || err
-  }</div>`;
+  statusCards.innerHTML = `<div class="empty-state">Error fetching data: ${escapeHTML(
+    err?.message || err,
+  )}</div>`;
 }
 
 refresh(currentRange, "initial-load");
@@ -1450,7 +1783,7 @@ function buildOverviewCard(item) {
     title.classList.add("stacked");
     kind.classList.add("secondary");
     kind.textContent = nodeLabel ? `(${nodeLabel})` : "(server)";
-    name.textContent = "Connectivity";
+    name.textContent = item?.name || "Connectivity";
   } else {
     kind.textContent = "Service";
   }
@@ -1487,6 +1820,8 @@ function mapOverviewState(state) {
       return "state-issue";
     case "ok":
       return "state-ok";
+    case "blocked":
+      return "state-blocked";
     default:
       return "state-unknown";
   }
@@ -12,12 +12,23 @@ import (
 	"jobmonitor/internal/models"
 )
 
+// latestRetention bounds how old a target's last result may be to still appear in Latest.
+const latestRetention = 31 * 24 * time.Hour
+
 // StatusStorage handles persistence of status history to disk.
 type StatusStorage struct {
 	mu      sync.RWMutex
 	path    string
 	history []models.StatusEntry
 	version uint64
+
+	latest map[string]latestCheck
+	order  []string
+}
+
+type latestCheck struct {
+	check models.CheckResult
+	at    time.Time
 }
 
 // NewStatusStorage creates a storage instance and loads existing history if present.
@@ -26,7 +37,7 @@ func NewStatusStorage(path string) (*StatusStorage, error) {
 		return nil, fmt.Errorf("ensure data directory: %w", err)
 	}
 
-	s := &StatusStorage{path: path}
+	s := &StatusStorage{path: path, latest: make(map[string]latestCheck)}
 	if err := s.load(); err != nil {
 		return nil, err
 	}
@@ -34,17 +45,27 @@ func NewStatusStorage(path string) (*StatusStorage, error) {
 	return s, nil
 }
 
-// Append adds a new status entry and persists it to disk.
+// Append adds a new status entry and persists it to disk. Entries stamped earlier than the
+// newest one (such as job runs reported after they finished) are inserted in order.
 func (s *StatusStorage) Append(entry models.StatusEntry) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.history = append(s.history, entry)
+	idx := sort.Search(len(s.history), func(i int) bool {
+		return s.history[i].Timestamp.After(entry.Timestamp)
+	})
+	s.history = append(s.history, models.StatusEntry{})
+	copy(s.history[idx+1:], s.history[idx:])
+	s.history[idx] = entry
+	s.track(entry)
 	s.version++
 	return s.persist()
 }
 
-// Latest returns the latest status entry if it exists.
+// Latest returns the most recent result of every target seen in the history, stamped with the
+// timestamp of the newest entry. Entries may hold only a subset of targets (events, per-target
+// schedules), so results are merged across entries. Targets without a result within
+// latestRetention of the newest entry are omitted.
 func (s *StatusStorage) Latest() (models.StatusEntry, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -52,7 +73,19 @@ func (s *StatusStorage) Latest() (models.StatusEntry, bool) {
 	if len(s.history) == 0 {
 		return models.StatusEntry{}, false
 	}
-	return s.history[len(s.history)-1], true
+	newest := s.history[len(s.history)-1].Timestamp
+	latest := models.StatusEntry{
+		Timestamp: newest,
+		Checks:    make([]models.CheckResult, 0, len(s.order)),
+	}
+	for _, id := range s.order {
+		last := s.latest[id]
+		if newest.Sub(last.at) > latestRetention {
+			continue
+		}
+		latest.Checks = append(latest.Checks, last.check)
+	}
+	return latest, true
 }
 
 // History returns a copy of the entire history slice.
@@ -122,10 +155,29 @@ func (s *StatusStorage) load() error {
 	}
 
 	s.history = entries
+	for _, entry := range entries {
+		s.track(entry)
+	}
 	s.version = uint64(len(s.history))
 	return nil
 }
 
+// track remembers the newest result per target in first-seen order.
+func (s *StatusStorage) track(entry models.StatusEntry) {
+	for _, check := range entry.Checks {
+		if check.ID == "" {
+			continue
+		}
+		last, ok := s.latest[check.ID]
+		if !ok {
+			s.order = append(s.order, check.ID)
+		} else if entry.Timestamp.Before(last.at) {
+			continue
+		}
+		s.latest[check.ID] = latestCheck{check: check, at: entry.Timestamp}
+	}
+}
+
 func (s *StatusStorage) persist() error {
 	bytes, err := json.MarshalIndent(s.history, "", "  ")
 	if err != nil {